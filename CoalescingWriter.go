@@ -0,0 +1,98 @@
+package goxdr
+
+import (
+	"io"
+	"net"
+)
+
+type CoalescingWriter struct {
+	Writer io.Writer
+	buffer []byte
+	err error
+}
+
+func NewCoalescingWriter(writer io.Writer, bufferSize int) *CoalescingWriter {
+	if bufferSize <= 0 {
+		bufferSize = defaultCoalescingBufferSize
+	} else if bufferSize < minCoalescingBufferSize {
+		bufferSize = minCoalescingBufferSize
+	}
+	return &CoalescingWriter {
+		Writer: writer,
+		buffer: make([]byte, 0, bufferSize),
+	}
+}
+
+func(coalescer *CoalescingWriter) Buffered() int {
+	return len(coalescer.buffer)
+}
+
+func(coalescer *CoalescingWriter) Available() int {
+	return cap(coalescer.buffer) - len(coalescer.buffer)
+}
+
+func(coalescer *CoalescingWriter) Reset(writer io.Writer) {
+	coalescer.Writer = writer
+	coalescer.buffer = coalescer.buffer[0:0]
+	coalescer.err = nil
+}
+
+func(coalescer *CoalescingWriter) Write(bytes []byte) (writeCount int, err error) {
+	if coalescer.err != nil {
+		return 0, coalescer.err
+	}
+	if cap(coalescer.buffer) == 0 {
+		coalescer.buffer = make([]byte, 0, defaultCoalescingBufferSize)
+	}
+	if len(bytes) <= coalescer.Available() {
+		coalescer.buffer = append(coalescer.buffer, bytes...)
+		return len(bytes), nil
+	}
+	if len(bytes) < cap(coalescer.buffer) {
+		err = coalescer.Flush()
+		if err != nil {
+			return
+		}
+		coalescer.buffer = append(coalescer.buffer, bytes...)
+		return len(bytes), nil
+	}
+	pending := len(coalescer.buffer)
+	vector := net.Buffers{coalescer.buffer, bytes}
+	written, err := vector.WriteTo(coalescer.Writer)
+	coalescer.buffer = coalescer.buffer[0:0]
+	if written > int64(pending) {
+		writeCount = int(written - int64(pending))
+	}
+	if err == nil && writeCount < len(bytes) {
+		err = io.ErrShortWrite
+	}
+	coalescer.err = err
+	return
+}
+
+func(coalescer *CoalescingWriter) Flush() (err error) {
+	if coalescer.err != nil {
+		return coalescer.err
+	}
+	if len(coalescer.buffer) == 0 {
+		return
+	}
+	writeCount, err := coalescer.Writer.Write(coalescer.buffer)
+	if err == nil && writeCount < len(coalescer.buffer) {
+		err = io.ErrShortWrite
+	}
+	coalescer.buffer = coalescer.buffer[0:0]
+	coalescer.err = err
+	return
+}
+
+func WriteCoalesced(packet Packet, buffer []byte, writer io.Writer) (err error) {
+	coalescer := NewCoalescingWriter(writer, 0)
+	err = packet.WriteTo(buffer, coalescer)
+	if err == nil {
+		err = coalescer.Flush()
+	}
+	return
+}
+
+var _ io.Writer = &CoalescingWriter{}
@@ -0,0 +1,128 @@
+package goxdr
+
+import (
+	"io"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type recordingWriter struct {
+	writes [][]byte
+	lastSlice []byte
+	limit int
+	failure error
+}
+
+func(writer *recordingWriter) Write(bytes []byte) (int, error) {
+	writer.writes = append(writer.writes, append([]byte{}, bytes...))
+	writer.lastSlice = bytes
+	if writer.limit > 0 && len(bytes) > writer.limit {
+		return writer.limit, writer.failure
+	}
+	return len(bytes), writer.failure
+}
+
+func(writer *recordingWriter) joined() []byte {
+	return bytes.Join(writer.writes, nil)
+}
+
+func TestCoalescingWriterBufferSize(t *testing.T) {
+	sizes := map[int]int {
+		-1: defaultCoalescingBufferSize,
+		0: defaultCoalescingBufferSize,
+		10: minCoalescingBufferSize,
+		100: 100,
+	}
+	for requested, expected := range sizes {
+		if available := NewCoalescingWriter(nil, requested).Available(); available != expected {
+			t.Fatalf("NewCoalescingWriter(%d) has room for %d bytes, want %d", requested, available, expected)
+		}
+	}
+}
+
+func TestCoalescingWriterBatchesSmallWrites(t *testing.T) {
+	sink := &recordingWriter{}
+	coalescer := NewCoalescingWriter(sink, 64)
+	for index := 0; index < 20; index++ {
+		WriteUint(uint32(index), make([]byte, 4), coalescer)
+	}
+	if len(sink.writes) != 1 || len(sink.writes[0]) != 64 || coalescer.Buffered() != 16 {
+		t.Fatalf("got %d writes with %d bytes buffered, want one full buffer", len(sink.writes), coalescer.Buffered())
+	}
+	if err := coalescer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.writes) != 2 || coalescer.Buffered() != 0 {
+		t.Fatalf("Flush left %d writes and %d bytes buffered", len(sink.writes), coalescer.Buffered())
+	}
+	var expected bytes.Buffer
+	for index := 0; index < 20; index++ {
+		WriteUint(uint32(index), make([]byte, 4), &expected)
+	}
+	if !bytes.Equal(sink.joined(), expected.Bytes()) {
+		t.Fatalf("wrote %x, want %x", sink.joined(), expected.Bytes())
+	}
+}
+
+func TestCoalescingWriterPassesLargeWritesThrough(t *testing.T) {
+	sink := &recordingWriter{}
+	coalescer := NewCoalescingWriter(sink, 64)
+	coalescer.Write([]byte("head"))
+	large := bytes.Repeat([]byte{0xAB}, 100)
+	writeCount, err := coalescer.Write(large)
+	if err != nil || writeCount != len(large) {
+		t.Fatalf("Write = %d, %v", writeCount, err)
+	}
+	if len(sink.writes) != 2 || string(sink.writes[0]) != "head" || &sink.lastSlice[0] != &large[0] {
+		t.Fatalf("got writes %q, want the buffered head and then the caller's slice", sink.writes)
+	}
+	if coalescer.Buffered() != 0 {
+		t.Fatalf("%d bytes left buffered", coalescer.Buffered())
+	}
+}
+
+func TestCoalescingWriterKeepsErrors(t *testing.T) {
+	failure := errors.New("broken pipe")
+	sink := &recordingWriter {
+		failure: failure,
+	}
+	coalescer := NewCoalescingWriter(sink, 64)
+	coalescer.Write([]byte("abc"))
+	if err := coalescer.Flush(); err != failure {
+		t.Fatalf("Flush = %v, want %v", err, failure)
+	}
+	if _, err := coalescer.Write([]byte("d")); err != failure {
+		t.Fatalf("Write after failure = %v, want %v", err, failure)
+	}
+	if err := coalescer.Flush(); err != failure || len(sink.writes) != 1 {
+		t.Fatalf("Flush after failure = %v with %d writes", err, len(sink.writes))
+	}
+	short := &recordingWriter {
+		limit: 2,
+	}
+	coalescer.Reset(short)
+	coalescer.Write([]byte("abc"))
+	if err := coalescer.Flush(); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("Flush after short write = %v, want io.ErrShortWrite", err)
+	}
+	coalescer.Reset(short)
+	if _, err := coalescer.Write(bytes.Repeat([]byte{1}, 100)); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("large short write = %v, want io.ErrShortWrite", err)
+	}
+}
+
+func TestWriteCoalesced(t *testing.T) {
+	sink := &recordingWriter{}
+	packet := &UnionPacket {
+		Discriminant: 3,
+		Arm: ByteSlicePacket {
+			Bytes: []byte{0, 0, 0, 7},
+		},
+		Cases: AnyCase,
+	}
+	err := WriteCoalesced(packet, make([]byte, 8), sink)
+	if err != nil || len(sink.writes) != 1 || !bytes.Equal(sink.joined(), []byte{0, 0, 0, 3, 0, 0, 0, 7}) {
+		t.Fatalf("WriteCoalesced = %v with writes %x, want one write", err, sink.writes)
+	}
+}
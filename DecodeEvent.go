@@ -0,0 +1,60 @@
+package goxdr
+
+type DecodeEventKind int
+
+const (
+	EventPrimitive DecodeEventKind = iota
+	EventBeginArray
+	EventBeginElement
+	EventEndArray
+	EventBeginOpaque
+	EventOpaqueData
+	EventEndOpaque
+	EventBeginUnion
+	EventEndUnion
+	EventBeginStruct
+	EventEndStruct
+)
+
+func(kind DecodeEventKind) String() string {
+	switch kind {
+		case EventPrimitive:
+			return "primitive"
+		case EventBeginArray:
+			return "begin array"
+		case EventBeginElement:
+			return "begin element"
+		case EventEndArray:
+			return "end array"
+		case EventBeginOpaque:
+			return "begin opaque"
+		case EventOpaqueData:
+			return "opaque data"
+		case EventEndOpaque:
+			return "end opaque"
+		case EventBeginUnion:
+			return "begin union"
+		case EventEndUnion:
+			return "end union"
+		case EventBeginStruct:
+			return "begin struct"
+		case EventEndStruct:
+			return "end struct"
+		default:
+			return "unknown event"
+	}
+}
+
+type DecodeEvent struct {
+	Kind DecodeEventKind
+	Name string
+	Offset uint64
+	Length uint32
+	Index uint32
+	Discriminant uint32
+	Signed bool
+	Arm string
+	Bytes []byte
+}
+
+type DecodeObserver func(*DecodeEvent)
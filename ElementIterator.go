@@ -0,0 +1,51 @@
+package goxdr
+
+type ElementCallback[T any] func(uint32, TypedReadState[T]) error
+
+type completedElement[T any] struct {
+	index uint32
+	element TypedReadState[T]
+}
+
+type ElementIterator[T any] struct {
+	queue []completedElement[T]
+	head int
+	current completedElement[T]
+}
+
+func NewElementIterator[T any]() *ElementIterator[T] {
+	return &ElementIterator[T]{}
+}
+
+func(iterator *ElementIterator[T]) Callback(index uint32, element TypedReadState[T]) error {
+	iterator.queue = append(iterator.queue, completedElement[T] {
+		index: index,
+		element: element,
+	})
+	return nil
+}
+
+func(iterator *ElementIterator[T]) Pending() int {
+	return len(iterator.queue) - iterator.head
+}
+
+func(iterator *ElementIterator[T]) Next() bool {
+	if iterator.head >= len(iterator.queue) {
+		iterator.current = completedElement[T]{}
+		iterator.queue = iterator.queue[0:0]
+		iterator.head = 0
+		return false
+	}
+	iterator.current = iterator.queue[iterator.head]
+	iterator.queue[iterator.head] = completedElement[T]{}
+	iterator.head++
+	return true
+}
+
+func(iterator *ElementIterator[T]) Index() uint32 {
+	return iterator.current.index
+}
+
+func(iterator *ElementIterator[T]) Element() TypedReadState[T] {
+	return iterator.current.element
+}
@@ -0,0 +1,58 @@
+package goxdr
+
+import (
+	"testing"
+)
+
+func TestElementIteratorAcrossChunks(t *testing.T) {
+	data := []byte {
+		0, 0, 0, 10,
+		0, 0, 0, 11,
+		0, 0, 0, 12,
+		0, 0, 0, 13,
+	}
+	for _, chunkSize := range []int{1, 3, 5, 16} {
+		iterator := NewElementIterator[uint32]()
+		state := &FixedLengthArrayReadState[uint32] {
+			ExpectedLength: 4,
+			HandlerFactory: primitiveFactory,
+			ElementDone: iterator.Callback,
+		}
+		var seen []uint32
+		drain := func() {
+			for iterator.Next() {
+				value := iterator.Element().(*PrimitiveReadState).AsUint()
+				if value != 10 + iterator.Index() {
+					t.Fatalf("chunk %d: element %d = %d", chunkSize, iterator.Index(), value)
+				}
+				seen = append(seen, iterator.Index())
+			}
+		}
+		for start := 0; start < len(data); start += chunkSize {
+			end := start + chunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			readCount, _ := state.Update(data[start:end])
+			if readCount != end - start {
+				t.Fatalf("chunk %d: Update consumed %d of %d", chunkSize, readCount, end - start)
+			}
+			drain()
+		}
+		if err := state.EndPacket(); err != nil {
+			t.Fatal(err)
+		}
+		drain()
+		if len(seen) != 4 {
+			t.Fatalf("chunk %d: saw %v, want 4 elements", chunkSize, seen)
+		}
+		for position, index := range seen {
+			if index != uint32(position) {
+				t.Fatalf("chunk %d: saw %v, want ascending order", chunkSize, seen)
+			}
+		}
+		if iterator.Pending() != 0 {
+			t.Fatalf("chunk %d: %d elements still pending", chunkSize, iterator.Pending())
+		}
+	}
+}
@@ -1,39 +1,100 @@
 package goxdr
 
 import (
-	"fmt"
-	"errors"
+	"strconv"
 )
 
 type FixedLengthArrayReadState[T any] struct {
 	ExpectedLength uint32
 	HandlerFactory TypedReadStateFactory[T]
 	HandlerName string
+	DecodeContext *DecodeContext
+	ElementDone ElementCallback[T]
 	currentIndex uint32
 	currentHandler ReadState
+	begun bool
+	offset uint64
+	handlerOffset uint64
 	firstError error
 }
 
 func(state *FixedLengthArrayReadState[T]) Reset() {
 	state.currentIndex = 0
 	state.currentHandler = nil
+	state.begun = false
+	state.offset = 0
+	state.handlerOffset = 0
 	state.firstError = nil
 }
 
+func(state *FixedLengthArrayReadState[T]) InheritDecodeContext(context *DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *FixedLengthArrayReadState[T]) elementName() string {
+	return state.HandlerName + "[" + strconv.FormatUint(uint64(state.currentIndex), 10) + "]"
+}
+
+func(state *FixedLengthArrayReadState[T]) observe(kind DecodeEventKind) {
+	if state.DecodeContext.Observing() {
+		state.DecodeContext.Observe(&DecodeEvent {
+			Kind: kind,
+			Name: state.HandlerName,
+			Offset: state.DecodeContext.ByteCount(),
+			Length: state.ExpectedLength,
+			Index: state.currentIndex,
+		})
+	}
+}
+
+func(state *FixedLengthArrayReadState[T]) begin() {
+	if !state.begun {
+		state.begun = true
+		state.observe(EventBeginArray)
+		if state.ExpectedLength == 0 {
+			state.observe(EventEndArray)
+		}
+	}
+}
+
 func(state *FixedLengthArrayReadState[T]) nextHandler() bool {
-	state.currentHandler, state.firstError = state.HandlerFactory(state.currentIndex, state.ExpectedLength)
-	if state.firstError != nil {
+	if state.currentIndex == 0 {
+		err := state.DecodeContext.allocateElements(state.ExpectedLength)
+		if err != nil {
+			state.firstError = WrapDecodeError(err, state.HandlerName, state.offset)
+			return true
+		}
+	}
+	state.handlerOffset = state.offset
+	var err error
+	state.currentHandler, err = state.HandlerFactory(state.currentIndex, state.ExpectedLength)
+	if err == nil && state.currentHandler == nil {
+		err = &NilReadStateError {
+			Index: state.currentIndex,
+			Length: state.ExpectedLength,
+		}
+	}
+	if err != nil {
+		state.firstError = WrapDecodeError(err, state.elementName(), state.handlerOffset)
 		return true
 	}
-	if state.currentHandler != nil {
-		return false
+	passDecodeContext(state.currentHandler, state.DecodeContext)
+	state.observe(EventBeginElement)
+	return false
+}
+
+func(state *FixedLengthArrayReadState[T]) endHandler() bool {
+	err := state.currentHandler.EndPacket()
+	if err == nil && state.ElementDone != nil {
+		err = state.ElementDone(state.currentIndex, state.currentHandler)
+	}
+	if err != nil {
+		state.firstError = WrapDecodeError(err, state.elementName(), state.handlerOffset)
+		return true
 	}
-	state.firstError = errors.New(fmt.Sprintf(
-		"Read state factory returned nil for index %d of %d",
-		state.currentIndex,
-		state.ExpectedLength,
-	))
-	return true
+	return false
 }
 
 func(state *FixedLengthArrayReadState[T]) Update(bytes []byte) (readCount int, isFull bool) {
@@ -41,10 +102,18 @@ func(state *FixedLengthArrayReadState[T]) Update(bytes []byte) (readCount int, i
 		isFull = true
 		return
 	}
+	state.begin()
 	if state.currentIndex >= state.ExpectedLength {
 		isFull = true
 		return
 	}
+	err := state.DecodeContext.Enter()
+	if err != nil {
+		state.firstError = WrapDecodeError(err, state.HandlerName, state.offset)
+		isFull = true
+		return
+	}
+	defer state.DecodeContext.Leave()
 	if state.currentHandler == nil {
 		isFull = state.nextHandler()
 		if isFull {
@@ -55,12 +124,11 @@ func(state *FixedLengthArrayReadState[T]) Update(bytes []byte) (readCount int, i
 	for {
 		handled, isFull = state.currentHandler.Update(bytes[readCount:])
 		readCount += handled
+		state.offset += uint64(handled)
 		if !isFull {
 			return
 		}
-		state.firstError = state.currentHandler.EndPacket()
-		if state.firstError != nil {
-			isFull = true
+		if state.endHandler() {
 			return
 		}
 		state.currentIndex++
@@ -71,6 +139,7 @@ func(state *FixedLengthArrayReadState[T]) Update(bytes []byte) (readCount int, i
 			}
 		} else {
 			state.currentHandler = nil
+			state.observe(EventEndArray)
 			isFull = true
 			return
 		}
@@ -78,14 +147,27 @@ func(state *FixedLengthArrayReadState[T]) Update(bytes []byte) (readCount int, i
 }
 
 func(state *FixedLengthArrayReadState[T]) EndPacket() (err error) {
+	if state.firstError == nil {
+		state.begin()
+	}
 	if state.firstError == nil && state.currentIndex < state.ExpectedLength {
+		err = state.DecodeContext.Enter()
+		if err != nil {
+			state.firstError = WrapDecodeError(err, state.HandlerName, state.offset)
+			return state.firstError
+		}
+		defer state.DecodeContext.Leave()
+		if state.currentHandler == nil && state.nextHandler() {
+			return state.firstError
+		}
 		for {
-			state.firstError = state.currentHandler.EndPacket()
-			if state.firstError != nil {
+			if state.endHandler() {
 				break
 			}
 			state.currentIndex++
 			if state.currentIndex >= state.ExpectedLength {
+				state.currentHandler = nil
+				state.observe(EventEndArray)
 				break
 			}
 			if state.nextHandler() {
@@ -96,4 +178,4 @@ func(state *FixedLengthArrayReadState[T]) EndPacket() (err error) {
 	return state.firstError
 }
 
-var _ ReadState = &FixedLengthArrayReadState[int]{}
+var _ ContextualReadState = &FixedLengthArrayReadState[int]{}
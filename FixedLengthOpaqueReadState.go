@@ -1,92 +1,211 @@
 package goxdr
 
-import (
-	"fmt"
-	"math"
-	"errors"
+type PaddingPolicy int
+
+const (
+	PaddingLenient PaddingPolicy = iota
+	PaddingStrict
 )
 
 type FixedLengthOpaqueReadState struct {
 	ExpectedLength uint32
 	Handler ReadState
 	HandlerName string
-	currentLength uint32
+	PaddingPolicy PaddingPolicy
+	DecodeContext *DecodeContext
+	Progress ProgressFunc
+	currentLength uint64
+	paddingViolations uint32
+	begun bool
+	ended bool
 	firstError error
 }
 
 func(state *FixedLengthOpaqueReadState) Reset() {
 	state.currentLength = 0
+	state.paddingViolations = 0
+	state.begun = false
+	state.ended = false
 	state.firstError = nil
 }
 
+func(state *FixedLengthOpaqueReadState) InheritDecodeContext(context *DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *FixedLengthOpaqueReadState) PaddingViolations() uint32 {
+	return state.paddingViolations
+}
+
+func(state *FixedLengthOpaqueReadState) paddedLength() uint64 {
+	return (uint64(state.ExpectedLength) + uint64(3)) &^ uint64(3)
+}
+
+func(state *FixedLengthOpaqueReadState) observe(kind DecodeEventKind, offset uint64, bytes []byte) {
+	if state.DecodeContext.Observing() {
+		state.DecodeContext.Observe(&DecodeEvent {
+			Kind: kind,
+			Name: state.HandlerName,
+			Offset: offset,
+			Length: state.ExpectedLength,
+			Bytes: bytes,
+		})
+	}
+}
+
+func(state *FixedLengthOpaqueReadState) begin() {
+	if !state.begun {
+		state.begun = true
+		state.observe(EventBeginOpaque, state.DecodeContext.ByteCount(), nil)
+	}
+}
+
+func(state *FixedLengthOpaqueReadState) end() {
+	if !state.ended {
+		state.ended = true
+		state.observe(EventEndOpaque, state.DecodeContext.ByteCount(), nil)
+		if state.ExpectedLength == 0 && state.Progress != nil {
+			state.Progress(0, 0)
+		}
+	}
+}
+
+func(state *FixedLengthOpaqueReadState) fail(err error) {
+	state.firstError = WrapDecodeError(err, state.HandlerName, state.currentLength)
+}
+
+func(state *FixedLengthOpaqueReadState) handlerError(err error) error {
+	if handlerError, ok := err.(*OpaqueHandlerError); ok {
+		return WrapDecodeError(handlerError, state.HandlerName, handlerError.Offset)
+	}
+	offset := state.currentLength
+	if offset > uint64(state.ExpectedLength) {
+		offset = uint64(state.ExpectedLength)
+	}
+	return WrapDecodeError(&OpaqueHandlerError {
+		PropagatedError: err,
+		Offset: offset,
+	}, state.HandlerName, offset)
+}
+
+func(state *FixedLengthOpaqueReadState) checkPadding(padding []byte) bool {
+	for index, value := range padding {
+		if value == 0 {
+			continue
+		}
+		if state.PaddingPolicy == PaddingStrict {
+			offset := state.currentLength + uint64(index)
+			state.firstError = WrapDecodeError(&NonZeroPaddingError {
+				Offset: offset,
+				Value: value,
+			}, state.HandlerName, offset)
+			return false
+		}
+		state.paddingViolations++
+		state.DecodeContext.notePaddingViolation()
+	}
+	return true
+}
+
 func(state *FixedLengthOpaqueReadState) Update(bytes []byte) (readCount int, isFull bool) {
 	if state.firstError != nil {
 		isFull = true
 		return
 	}
-	length := len(bytes)
-	if int64(length) > int64(math.MaxUint32) {
-		state.firstError = errors.New(fmt.Sprintf(
-			"Update with chunk of size %d, which exceeds the range of uint32",
-			length,
-		))
-		isFull = true
-		return
-	}
-	length32 := uint32(length)
-	nextLength := state.currentLength + length32
-	if nextLength < length32 {
-		state.firstError = errors.New(fmt.Sprintf(
-			"Update with chunk of size %d would cause total stream size (%d before chunk) " +
-					"to exceed the range of uint32",
-			length32,
-			state.currentLength,
-		))
+	err := state.DecodeContext.checkStringLength(state.ExpectedLength)
+	if err != nil {
+		state.fail(err)
 		isFull = true
 		return
 	}
-	paddedExpectedLength := state.ExpectedLength
-	remainder := paddedExpectedLength % uint32(4)
-	if remainder > 0 {
-		paddedExpectedLength += uint32(4) - remainder
-	}
-	paddedLength := length32
-	if length32 > paddedExpectedLength {
-		length32 = state.ExpectedLength - state.currentLength
-		paddedLength = paddedExpectedLength - state.currentLength
-	} else if length32 > state.ExpectedLength {
-		length32 = state.ExpectedLength - state.currentLength
-	}
-	var handled uint32
-	if length32 > 0 {
-		readCount, isFull = state.Handler.Update(bytes[0:length32])
-		if uint32(readCount) > length32 {
-			state.firstError = errors.New(fmt.Sprintf(
-				"Opaque data handler read %d bytes, but was supposed to only read %d",
-				readCount,
-				length32,
-			))
+	state.begin()
+	length := uint64(len(bytes))
+	expectedLength := uint64(state.ExpectedLength)
+	if state.currentLength < expectedLength {
+		dataLength := expectedLength - state.currentLength
+		if dataLength > length {
+			dataLength = length
+		}
+		if dataLength == 0 {
+			return
+		}
+		if budget, limited := state.DecodeContext.remainingBytes(); limited && dataLength > budget {
+			if budget == 0 {
+				state.fail(state.DecodeContext.consumeBytes(int(dataLength)))
+				isFull = true
+				return
+			}
+			dataLength = budget
+		}
+		var handlerFull bool
+		readCount, handlerFull = state.Handler.Update(bytes[0:dataLength])
+		if readCount < 0 || uint64(readCount) > dataLength {
+			state.fail(&OverreadError {
+				Subject: "Opaque data handler",
+				ReadCount: readCount,
+				Offered: int(dataLength),
+			})
+			readCount = 0
 			isFull = true
 			return
 		}
-		handled = uint32(readCount)
+		err = state.DecodeContext.consumeBytes(readCount)
+		if err != nil {
+			state.fail(err)
+			isFull = true
+			return
+		}
+		if readCount > 0 {
+			state.observe(EventOpaqueData, state.DecodeContext.ByteCount() - uint64(readCount), bytes[0:readCount])
+		}
+		state.currentLength += uint64(readCount)
+		if readCount > 0 && state.Progress != nil {
+			state.Progress(state.currentLength, expectedLength)
+		}
+		if uint64(readCount) < dataLength {
+			if handlerFull {
+				err = state.Handler.EndPacket()
+				if err != nil {
+					state.firstError = state.handlerError(err)
+				} else {
+					state.fail(&LengthMismatchError {
+						Subject: "opaque data handler",
+						Expected: expectedLength,
+						Actual: state.currentLength,
+					})
+				}
+				isFull = true
+			}
+			return
+		}
+		if state.currentLength < expectedLength {
+			return
+		}
 	}
-	if handled < length32 {
-		state.currentLength += handled
-	} else {
-		readCount = int(paddedLength)
-		if readCount < 0 {
-			readCount = math.MaxInt
-			paddedLength = uint32(readCount)
+	paddingLength := state.paddedLength() - state.currentLength
+	if rest := length - uint64(readCount); paddingLength > rest {
+		paddingLength = rest
+	}
+	if paddingLength > 0 {
+		padding := bytes[readCount:uint64(readCount) + paddingLength]
+		err = state.DecodeContext.consumeBytes(len(padding))
+		if err != nil {
+			state.fail(err)
+			isFull = true
+			return
 		}
-		if readCount > length {
-			readCount = length
-			paddedLength = uint32(readCount)
+		if !state.checkPadding(padding) {
+			isFull = true
+			return
 		}
-		state.currentLength += paddedLength
+		readCount += len(padding)
+		state.currentLength += paddingLength
 	}
-	if state.currentLength >= paddedExpectedLength {
-		isFull = true
+	isFull = state.currentLength >= state.paddedLength()
+	if isFull {
+		state.end()
 	}
 	return
 }
@@ -94,16 +213,23 @@ func(state *FixedLengthOpaqueReadState) Update(bytes []byte) (readCount int, isF
 func(state *FixedLengthOpaqueReadState) EndPacket() (err error) {
 	if state.firstError != nil {
 		err = state.firstError
+	} else if paddedLength := state.paddedLength(); state.currentLength < paddedLength {
+		state.fail(&TruncatedError {
+			Subject: "opaque data",
+			Size: paddedLength,
+			Missing: paddedLength - state.currentLength,
+		})
+		err = state.firstError
 	} else {
+		state.begin()
+		state.end()
 		err = state.Handler.EndPacket()
 		if err != nil {
-			err = &OpaqueHandlerError {
-				PropagatedError: err,
-				HandlerName: state.HandlerName,
-			}
+			state.firstError = state.handlerError(err)
+			err = state.firstError
 		}
 	}
 	return
 }
 
-var _ ReadState = &FixedLengthOpaqueReadState{}
+var _ ContextualReadState = &FixedLengthOpaqueReadState{}
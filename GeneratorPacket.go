@@ -0,0 +1,187 @@
+package goxdr
+
+import (
+	"io"
+)
+
+type FixedLengthOpaqueGeneratorPacket struct {
+	Generator ByteGenerator
+	ExpectedSize uint32
+	Padding BytePadder
+}
+
+func(packet *FixedLengthOpaqueGeneratorPacket) ByteSize() uint32 {
+	return sizeOrZero(MeasureFixedLengthOpaqueGenerator(packet.ExpectedSize))
+}
+
+func(packet *FixedLengthOpaqueGeneratorPacket) SizeError() (err error) {
+	_, err = MeasureFixedLengthOpaqueGenerator(packet.ExpectedSize)
+	return
+}
+
+func(packet *FixedLengthOpaqueGeneratorPacket) WriteTo(buffer []byte, writer io.Writer) (err error) {
+	_, err = MeasureFixedLengthOpaqueGenerator(packet.ExpectedSize)
+	if err == nil {
+		err = WriteFixedLengthOpaqueGenerator(packet.Generator, packet.ExpectedSize, buffer, writer, packet.Padding)
+	}
+	return
+}
+
+type VariableLengthOpaqueGeneratorPacket struct {
+	Generator ByteGenerator
+	ExpectedSize uint32
+	MaxSize uint32
+	Padding BytePadder
+}
+
+func(packet *VariableLengthOpaqueGeneratorPacket) ByteSize() uint32 {
+	return sizeOrZero(MeasureVariableLengthOpaqueGenerator(packet.ExpectedSize, packet.MaxSize))
+}
+
+func(packet *VariableLengthOpaqueGeneratorPacket) SizeError() (err error) {
+	_, err = MeasureVariableLengthOpaqueGenerator(packet.ExpectedSize, packet.MaxSize)
+	return
+}
+
+func(packet *VariableLengthOpaqueGeneratorPacket) WriteTo(buffer []byte, writer io.Writer) (err error) {
+	_, err = MeasureVariableLengthOpaqueGenerator(packet.ExpectedSize, packet.MaxSize)
+	if err == nil {
+		err = WriteVariableLengthOpaqueGenerator(
+			packet.Generator,
+			packet.ExpectedSize,
+			packet.MaxSize,
+			buffer,
+			writer,
+			packet.Padding,
+		)
+	}
+	return
+}
+
+type measuredSize struct {
+	measured bool
+	size uint32
+	sizeError error
+}
+
+func(cache *measuredSize) Invalidate() {
+	cache.measured = false
+	cache.size = 0
+	cache.sizeError = nil
+}
+
+func(cache *measuredSize) SizeError() error {
+	return cache.sizeError
+}
+
+func sizeOrZero(size uint32, err error) uint32 {
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+func(cache *measuredSize) store(size uint32, err error) (uint32, error) {
+	cache.measured = true
+	cache.size = size
+	cache.sizeError = err
+	return size, err
+}
+
+func(cache *measuredSize) check(actualSize uint32) (err error) {
+	if cache.measured && actualSize != cache.size {
+		err = &LengthMismatchError {
+			Subject: "generated packet",
+			Expected: uint64(cache.size),
+			Actual: uint64(actualSize),
+		}
+	}
+	return
+}
+
+// The generator is run once by ByteSize/Measure and again by WriteTo, so it
+// must replay the same elements each time. A failed measurement is cached
+// and makes WriteTo fail before anything is written.
+type FixedLengthArrayGeneratorPacket[T any] struct {
+	Generator PacketGenerator[T]
+	ExpectedSize uint32
+	Padding ElementPadder[T]
+	measuredSize
+}
+
+func(packet *FixedLengthArrayGeneratorPacket[T]) Measure(buffer []byte) (uint32, error) {
+	if packet.measured {
+		return packet.size, packet.sizeError
+	}
+	return packet.store(MeasureFixedLengthArrayGenerator(packet.Generator, packet.ExpectedSize, buffer, packet.Padding))
+}
+
+func(packet *FixedLengthArrayGeneratorPacket[T]) ByteSize() uint32 {
+	return sizeOrZero(packet.Measure(make([]byte, minScratchBufferSize)))
+}
+
+func(packet *FixedLengthArrayGeneratorPacket[T]) WriteTo(buffer []byte, writer io.Writer) (err error) {
+	if packet.measured && packet.sizeError != nil {
+		err = packet.sizeError
+		return
+	}
+	var counter countingWriter
+	counter.writer = writer
+	err = WriteFixedLengthArrayGenerator(packet.Generator, packet.ExpectedSize, buffer, &counter, packet.Padding)
+	if err == nil {
+		err = packet.check(counter.count)
+	}
+	return
+}
+
+// Same replay requirement as FixedLengthArrayGeneratorPacket.
+type VariableLengthArrayGeneratorPacket[T any] struct {
+	Generator PacketGenerator[T]
+	ExpectedSize uint32
+	MaxSize uint32
+	Padding ElementPadder[T]
+	measuredSize
+}
+
+func(packet *VariableLengthArrayGeneratorPacket[T]) Measure(buffer []byte) (uint32, error) {
+	if packet.measured {
+		return packet.size, packet.sizeError
+	}
+	return packet.store(MeasureVariableLengthArrayGenerator(
+		packet.Generator,
+		packet.ExpectedSize,
+		packet.MaxSize,
+		buffer,
+		packet.Padding,
+	))
+}
+
+func(packet *VariableLengthArrayGeneratorPacket[T]) ByteSize() uint32 {
+	return sizeOrZero(packet.Measure(make([]byte, minScratchBufferSize)))
+}
+
+func(packet *VariableLengthArrayGeneratorPacket[T]) WriteTo(buffer []byte, writer io.Writer) (err error) {
+	if packet.measured && packet.sizeError != nil {
+		err = packet.sizeError
+		return
+	}
+	var counter countingWriter
+	counter.writer = writer
+	err = WriteVariableLengthArrayGenerator(
+		packet.Generator,
+		packet.ExpectedSize,
+		packet.MaxSize,
+		buffer,
+		&counter,
+		packet.Padding,
+	)
+	if err == nil {
+		err = packet.check(counter.count)
+	}
+	return
+}
+
+var _ Packet = &FixedLengthOpaqueGeneratorPacket{}
+var _ Packet = &VariableLengthOpaqueGeneratorPacket{}
+var _ Packet = &FixedLengthArrayGeneratorPacket[int]{}
+var _ Packet = &VariableLengthArrayGeneratorPacket[int]{}
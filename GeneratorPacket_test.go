@@ -0,0 +1,96 @@
+package goxdr
+
+import (
+	"math"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func intElements(count int) PacketGenerator[int] {
+	return func(sink PacketSink[int]) error {
+		for i := 0; i < count; i++ {
+			err := sink(ByteSlicePacket {
+				Bytes: []byte{0, 0, 0, byte(i)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestArrayGeneratorPacketMeasureFailure(t *testing.T) {
+	packet := &VariableLengthArrayGeneratorPacket[int] {
+		Generator: intElements(3),
+		ExpectedSize: 2,
+		MaxSize: 10,
+	}
+	if size := packet.ByteSize(); size != 0 {
+		t.Fatalf("ByteSize after failed measurement = %d, want 0", size)
+	}
+	if !errors.Is(packet.SizeError(), ErrLengthMismatch) {
+		t.Fatalf("SizeError = %v, want length mismatch", packet.SizeError())
+	}
+	var out bytes.Buffer
+	err := packet.WriteTo(make([]byte, 8), &out)
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("WriteTo = %v, want length mismatch", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("WriteTo wrote %d bytes despite failed measurement", out.Len())
+	}
+}
+
+func TestArrayGeneratorPacketReplay(t *testing.T) {
+	packet := &FixedLengthArrayGeneratorPacket[int] {
+		Generator: intElements(3),
+		ExpectedSize: 3,
+	}
+	if size := packet.ByteSize(); size != 12 {
+		t.Fatalf("ByteSize = %d, want 12", size)
+	}
+	var out bytes.Buffer
+	err := packet.WriteTo(make([]byte, 8), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 12 {
+		t.Fatalf("WriteTo wrote %d bytes, want 12", out.Len())
+	}
+}
+
+func TestOpaqueGeneratorPacketSizeError(t *testing.T) {
+	packets := map[string]interface {
+		Packet
+		SizeError() error
+	} {
+		"over maximum": &VariableLengthOpaqueGeneratorPacket {
+			ExpectedSize: 9,
+			MaxSize: 8,
+		},
+		"fixed overflow": &FixedLengthOpaqueGeneratorPacket {
+			ExpectedSize: math.MaxUint32,
+		},
+		"variable overflow": &VariableLengthOpaqueGeneratorPacket {
+			ExpectedSize: math.MaxUint32 - 3,
+			MaxSize: math.MaxUint32,
+		},
+	}
+	for name, packet := range packets {
+		if size := packet.ByteSize(); size != 0 {
+			t.Fatalf("%s: ByteSize = %d, want 0", name, size)
+		}
+		if packet.SizeError() == nil {
+			t.Fatalf("%s: SizeError = nil", name)
+		}
+	}
+	packet := &VariableLengthOpaqueGeneratorPacket {
+		ExpectedSize: 5,
+		MaxSize: 8,
+	}
+	if packet.ByteSize() != 12 || packet.SizeError() != nil {
+		t.Fatalf("ByteSize = %d, SizeError = %v, want 12 and nil", packet.ByteSize(), packet.SizeError())
+	}
+}
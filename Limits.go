@@ -0,0 +1,182 @@
+package goxdr
+
+type Limits struct {
+	MaxMessageBytes uint64
+	MaxTotalElements uint64
+	MaxDepth uint32
+	MaxStringBytes uint32
+}
+
+var DefaultLimits Limits = Limits {
+	MaxMessageBytes: 64 << 20,
+	MaxTotalElements: 1 << 20,
+	MaxDepth: 64,
+	MaxStringBytes: 16 << 20,
+}
+
+type LimitKind int
+
+const (
+	LimitMessageBytes LimitKind = iota
+	LimitTotalElements
+	LimitDepth
+	LimitStringBytes
+)
+
+func(kind LimitKind) String() string {
+	switch kind {
+		case LimitMessageBytes:
+			return "message bytes"
+		case LimitTotalElements:
+			return "total elements"
+		case LimitDepth:
+			return "nesting depth"
+		case LimitStringBytes:
+			return "string bytes"
+		default:
+			return "unknown limit"
+	}
+}
+
+type DecodeContext struct {
+	Limits Limits
+	Observer DecodeObserver
+	byteCount uint64
+	elementCount uint64
+	depth uint32
+	paddingViolations uint64
+}
+
+func NewDecodeContext(limits Limits) *DecodeContext {
+	return &DecodeContext {
+		Limits: limits,
+	}
+}
+
+func(context *DecodeContext) Reset() {
+	if context != nil {
+		context.byteCount = 0
+		context.elementCount = 0
+		context.depth = 0
+		context.paddingViolations = 0
+	}
+}
+
+func(context *DecodeContext) ByteCount() uint64 {
+	if context == nil {
+		return 0
+	}
+	return context.byteCount
+}
+
+func(context *DecodeContext) ElementCount() uint64 {
+	if context == nil {
+		return 0
+	}
+	return context.elementCount
+}
+
+func(context *DecodeContext) Depth() uint32 {
+	if context == nil {
+		return 0
+	}
+	return context.depth
+}
+
+func(context *DecodeContext) PaddingViolations() uint64 {
+	if context == nil {
+		return 0
+	}
+	return context.paddingViolations
+}
+
+func(context *DecodeContext) Enter() error {
+	if context == nil {
+		return nil
+	}
+	if context.Limits.MaxDepth > 0 && context.depth >= context.Limits.MaxDepth {
+		return &LimitExceededError {
+			Limit: LimitDepth,
+			Maximum: uint64(context.Limits.MaxDepth),
+			Actual: uint64(context.depth) + 1,
+		}
+	}
+	context.depth++
+	return nil
+}
+
+func(context *DecodeContext) Leave() {
+	if context != nil && context.depth > 0 {
+		context.depth--
+	}
+}
+
+func(context *DecodeContext) consumeBytes(count int) error {
+	if context == nil || count <= 0 {
+		return nil
+	}
+	nextCount := context.byteCount + uint64(count)
+	if context.Limits.MaxMessageBytes > 0 && nextCount > context.Limits.MaxMessageBytes {
+		return &LimitExceededError {
+			Limit: LimitMessageBytes,
+			Maximum: context.Limits.MaxMessageBytes,
+			Actual: nextCount,
+		}
+	}
+	context.byteCount = nextCount
+	return nil
+}
+
+func(context *DecodeContext) remainingBytes() (remaining uint64, limited bool) {
+	if context == nil || context.Limits.MaxMessageBytes == 0 {
+		return
+	}
+	limited = true
+	if context.byteCount < context.Limits.MaxMessageBytes {
+		remaining = context.Limits.MaxMessageBytes - context.byteCount
+	}
+	return
+}
+
+func(context *DecodeContext) allocateElements(count uint32) error {
+	if context == nil || count == 0 {
+		return nil
+	}
+	nextCount := context.elementCount + uint64(count)
+	if context.Limits.MaxTotalElements > 0 && nextCount > context.Limits.MaxTotalElements {
+		return &LimitExceededError {
+			Limit: LimitTotalElements,
+			Maximum: context.Limits.MaxTotalElements,
+			Actual: nextCount,
+		}
+	}
+	context.elementCount = nextCount
+	return nil
+}
+
+func(context *DecodeContext) checkStringLength(length uint32) error {
+	if context == nil || context.Limits.MaxStringBytes == 0 || length <= context.Limits.MaxStringBytes {
+		return nil
+	}
+	return &LimitExceededError {
+		Limit: LimitStringBytes,
+		Maximum: uint64(context.Limits.MaxStringBytes),
+		Actual: uint64(length),
+	}
+}
+
+func(context *DecodeContext) notePaddingViolation() {
+	if context != nil {
+		context.paddingViolations++
+	}
+}
+
+func(context *DecodeContext) Observing() bool {
+	return context != nil && context.Observer != nil
+}
+
+func(context *DecodeContext) Observe(event *DecodeEvent) {
+	if context != nil && context.Observer != nil {
+		context.Observer(event)
+	}
+}
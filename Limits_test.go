@@ -0,0 +1,97 @@
+package goxdr
+
+import (
+	"errors"
+	"testing"
+)
+
+func primitiveFactory(uint32, uint32) (TypedReadState[uint32], error) {
+	return NewPrimitiveReadState(4)
+}
+
+func TestArrayElementsInheritDecodeContext(t *testing.T) {
+	context := NewDecodeContext(Limits {
+		MaxMessageBytes: 8,
+	})
+	state := &FixedLengthArrayReadState[uint32] {
+		ExpectedLength: 3,
+		HandlerFactory: primitiveFactory,
+		DecodeContext: context,
+	}
+	state.Update(make([]byte, 12))
+	var limitError *LimitExceededError
+	if err := state.EndPacket(); !errors.As(err, &limitError) || limitError.Limit != LimitMessageBytes {
+		t.Fatalf("EndPacket = %v, want message byte limit", err)
+	}
+}
+
+func TestUnionArmsInheritDecodeContext(t *testing.T) {
+	context := NewDecodeContext(Limits {
+		MaxMessageBytes: 6,
+	})
+	primitiveState, _ := NewPrimitiveReadState(4)
+	state := &TaggedUnionReadState[uint32] {
+		PrimitiveState: primitiveState,
+		Arms: map[uint32]TypedReadStateFactory[uint32] {
+			1: primitiveFactory,
+		},
+		DecodeContext: context,
+	}
+	state.Update([]byte{0, 0, 0, 1, 0, 0, 0, 2})
+	var limitError *LimitExceededError
+	if err := state.EndPacket(); !errors.As(err, &limitError) {
+		t.Fatalf("EndPacket = %v, want limit error", err)
+	}
+	if context.ByteCount() > 6 {
+		t.Fatalf("ByteCount = %d, exceeds limit", context.ByteCount())
+	}
+}
+
+func TestFixedLengthArrayEndPacketWithoutUpdate(t *testing.T) {
+	state := &FixedLengthArrayReadState[uint32] {
+		ExpectedLength: 1,
+		HandlerFactory: primitiveFactory,
+	}
+	if err := state.EndPacket(); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("EndPacket = %v, want truncation", err)
+	}
+}
+
+type byteCollector struct {
+	bytes []byte
+}
+
+func(collector *byteCollector) Update(bytes []byte) (int, bool) {
+	collector.bytes = append(collector.bytes, bytes...)
+	return len(bytes), false
+}
+
+func(collector *byteCollector) EndPacket() error {
+	return nil
+}
+
+func TestOpaqueHandlerNeverSeesBytesPastLimit(t *testing.T) {
+	data := []byte{0, 0, 0, 8, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'}
+	for _, limit := range []uint64{4, 6, 11} {
+		collector := &byteCollector{}
+		primitiveState, _ := NewPrimitiveReadState(4)
+		state := &VariableLengthOpaqueReadState {
+			PrimitiveState: primitiveState,
+			FixedLengthState: &FixedLengthOpaqueReadState {
+				Handler: collector,
+			},
+			MaxLength: 16,
+		}
+		state.InheritDecodeContext(NewDecodeContext(Limits {
+			MaxMessageBytes: limit,
+		}))
+		err := DecodeExact(state, data)
+		var limitError *LimitExceededError
+		if !errors.As(err, &limitError) || limitError.Limit != LimitMessageBytes {
+			t.Fatalf("limit %d: DecodeExact = %v, want message byte limit", limit, err)
+		}
+		if uint64(len(collector.bytes)) > limit - 4 {
+			t.Fatalf("limit %d: handler saw %q", limit, collector.bytes)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"io"
 	"fmt"
 	"math"
-	"errors"
 )
 
 type Packet interface {
@@ -48,11 +47,12 @@ func(packet *PaddingPacket) ByteSize() uint32 {
 func(packet *PaddingPacket) WriteTo(buffer []byte, writer io.Writer) (err error) {
 	shortLength := packet.ShortPacket.ByteSize()
 	if shortLength > packet.RequiredLength {
-		err = errors.New(fmt.Sprintf(
-			"Cannot pad Packet of size %d to size %d: Padding would be negative length",
-			shortLength,
-			packet.RequiredLength,
-		))
+		err = &LengthMismatchError {
+			Subject: "padded packet",
+			Expected: uint64(packet.RequiredLength),
+			Actual: uint64(shortLength),
+		}
+		return
 	}
 	err = packet.ShortPacket.WriteTo(buffer, writer)
 	if err != nil {
@@ -69,5 +69,27 @@ func(packet *PaddingPacket) WriteTo(buffer []byte, writer io.Writer) (err error)
 	return
 }
 
+type UnionPacket struct {
+	Discriminant uint32
+	Arm Packet
+	Cases *UnionCases
+}
+
+func(packet *UnionPacket) ByteSize() uint32 {
+	if packet.Arm == nil {
+		return 4
+	}
+	size := packet.Arm.ByteSize()
+	if size > math.MaxUint32 - 4 {
+		panic(fmt.Sprintf("Size of union arm (%d bytes) plus discriminant exceeds range of uint32", size))
+	}
+	return size + 4
+}
+
+func(packet *UnionPacket) WriteTo(buffer []byte, writer io.Writer) error {
+	return WriteUnion(packet.Discriminant, packet.Arm, packet.Cases, buffer, writer)
+}
+
 var _ Packet = ByteSlicePacket{}
 var _ Packet = &PaddingPacket{}
+var _ Packet = &UnionPacket{}
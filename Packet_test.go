@@ -0,0 +1,24 @@
+package goxdr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPaddingPacketRejectsLongPacket(t *testing.T) {
+	packet := &PaddingPacket {
+		ShortPacket: ByteSlicePacket {
+			Bytes: []byte{1, 2, 3, 4, 5},
+		},
+		RequiredLength: 4,
+	}
+	var out bytes.Buffer
+	err := packet.WriteTo(make([]byte, 8), &out)
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("WriteTo = %v, want length mismatch", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("WriteTo wrote %d bytes after rejecting the packet", out.Len())
+	}
+}
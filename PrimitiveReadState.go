@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"math"
 	"errors"
+	"encoding/binary"
 )
 
 type PrimitiveReadState struct {
+	HandlerName string
+	DecodeContext *DecodeContext
+	quiet bool
 	primitiveSize int
 	bytes [8]byte
 	fillCount int
+	firstError error
 }
 
 func NewPrimitiveReadState(primitiveSize int) (state *PrimitiveReadState, err error) {
@@ -29,13 +34,27 @@ func(state *PrimitiveReadState) Reset(primitiveSize int) error {
 		case 4, 8:
 			state.primitiveSize = primitiveSize
 			state.fillCount = 0
+			state.firstError = nil
 			return nil
 		default:
 			return errors.New(fmt.Sprintf("Expected primitive size to be 4 or 8, not %d", primitiveSize))
 	}
 }
 
+func(state *PrimitiveReadState) InheritDecodeContext(context *DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
 func(state *PrimitiveReadState) Update(bytes []byte) (readCount int, isFull bool) {
+	if state.firstError != nil {
+		isFull = true
+		return
+	}
+	if state.fillCount == 0 && len(bytes) >= state.primitiveSize {
+		return state.updateWhole(bytes)
+	}
 	if state.fillCount > state.primitiveSize {
 		panic(fmt.Sprintf("fillCount (%d) > primitiveSize (%d)", state.fillCount, state.primitiveSize))
 	}
@@ -46,66 +65,95 @@ func(state *PrimitiveReadState) Update(bytes []byte) (readCount int, isFull bool
 	} else {
 		readCount = need
 	}
-	for index := 0; index < readCount; index++ {
-		state.bytes[state.fillCount + index] = bytes[index]
+	state.firstError = state.DecodeContext.consumeBytes(readCount)
+	if state.firstError != nil {
+		readCount = 0
+		isFull = true
+		return
 	}
+	copy(state.bytes[state.fillCount:state.fillCount + readCount], bytes)
 	state.fillCount += readCount
 	if state.fillCount == state.primitiveSize {
 		isFull = true
+		if readCount > 0 {
+			state.observeFull()
+		}
+	}
+	return
+}
+
+func(state *PrimitiveReadState) updateWhole(bytes []byte) (readCount int, isFull bool) {
+	isFull = true
+	state.firstError = state.DecodeContext.consumeBytes(state.primitiveSize)
+	if state.firstError != nil {
+		return
+	}
+	if state.primitiveSize == 4 {
+		binary.BigEndian.PutUint32(state.bytes[0:4], binary.BigEndian.Uint32(bytes))
+	} else {
+		binary.BigEndian.PutUint64(state.bytes[0:8], binary.BigEndian.Uint64(bytes))
 	}
+	state.fillCount = state.primitiveSize
+	readCount = state.primitiveSize
+	state.observeFull()
 	return
 }
 
+func(state *PrimitiveReadState) observeFull() {
+	if !state.quiet && state.DecodeContext.Observing() {
+		state.DecodeContext.Observe(&DecodeEvent {
+			Kind: EventPrimitive,
+			Name: state.HandlerName,
+			Offset: state.DecodeContext.ByteCount() - uint64(state.primitiveSize),
+			Bytes: state.bytes[0:state.primitiveSize],
+		})
+	}
+}
+
 func(state *PrimitiveReadState) EndPacket() error {
+	if state.firstError != nil {
+		return state.firstError
+	}
 	if state.fillCount > state.primitiveSize {
 		panic(fmt.Sprintf("fillCount (%d) > primitiveSize (%d)", state.fillCount, state.primitiveSize))
 	}
 	if state.fillCount == state.primitiveSize {
 		return nil
 	}
-	return errors.New(fmt.Sprintf(
-		"Missing %d bytes for primitive of size %d",
-		state.primitiveSize - state.fillCount,
-		state.primitiveSize,
-	))
+	return &TruncatedError {
+		Subject: "primitive",
+		Size: uint64(state.primitiveSize),
+		Missing: uint64(state.primitiveSize - state.fillCount),
+	}
 }
 
 func(state *PrimitiveReadState) AsInt() int32 {
-	var digits uint32 = (uint32(state.bytes[0]) << 24) |
-		(uint32(state.bytes[1]) << 16) |
-		(uint32(state.bytes[2]) << 8) |
-		uint32(state.bytes[3])
-	return int32(digits)
+	return int32(binary.BigEndian.Uint32(state.bytes[0:4]))
 }
 
 func(state *PrimitiveReadState) AsUint() uint32 {
-	return (uint32(state.bytes[0]) << 24) |
-		(uint32(state.bytes[1]) << 16) |
-		(uint32(state.bytes[2]) << 8) |
-		uint32(state.bytes[3])
+	return binary.BigEndian.Uint32(state.bytes[0:4])
 }
 
 func(state *PrimitiveReadState) AsHyperInt() int64 {
-	var digits uint64 = (uint64(state.bytes[0]) << 56) |
-		(uint64(state.bytes[1]) << 48) |
-		(uint64(state.bytes[2]) << 40) |
-		(uint64(state.bytes[3]) << 32) |
-		(uint64(state.bytes[4]) << 24) |
-		(uint64(state.bytes[5]) << 16) |
-		(uint64(state.bytes[6]) << 8) |
-		uint64(state.bytes[7])
-	return int64(digits)
+	return int64(binary.BigEndian.Uint64(state.bytes[0:8]))
 }
 
 func(state *PrimitiveReadState) AsHyperUint() uint64 {
-	return (uint64(state.bytes[0]) << 56) |
-		(uint64(state.bytes[1]) << 48) |
-		(uint64(state.bytes[2]) << 40) |
-		(uint64(state.bytes[3]) << 32) |
-		(uint64(state.bytes[4]) << 24) |
-		(uint64(state.bytes[5]) << 16) |
-		(uint64(state.bytes[6]) << 8) |
-		uint64(state.bytes[7])
+	return binary.BigEndian.Uint64(state.bytes[0:8])
+}
+
+func(state *PrimitiveReadState) AsBool() (value bool, err error) {
+	switch digits := state.AsUint(); digits {
+		case 0:
+		case 1:
+			value = true
+		default:
+			err = &BoolError {
+				Value: digits,
+			}
+	}
+	return
 }
 
 func(state *PrimitiveReadState) AsFloat() float32 {
@@ -116,4 +164,4 @@ func(state *PrimitiveReadState) AsDouble() float64 {
 	return math.Float64frombits(state.AsHyperUint())
 }
 
-var _ ReadState = &PrimitiveReadState{}
+var _ ContextualReadState = &PrimitiveReadState{}
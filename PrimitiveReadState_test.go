@@ -0,0 +1,73 @@
+package goxdr
+
+import (
+	"bytes"
+	"testing"
+)
+
+type primitiveOutcome struct {
+	value uint64
+	readCount int
+	events []DecodeEvent
+}
+
+func decodePrimitive(t *testing.T, size int, chunks ...[]byte) (outcome primitiveOutcome) {
+	context := NewDecodeContext(DefaultLimits)
+	context.Observer = func(event *DecodeEvent) {
+		recorded := *event
+		recorded.Bytes = append([]byte{}, event.Bytes...)
+		outcome.events = append(outcome.events, recorded)
+	}
+	state, _ := NewPrimitiveReadState(size)
+	state.HandlerName = "value"
+	state.DecodeContext = context
+	for _, chunk := range chunks {
+		readCount, isFull := state.Update(chunk)
+		outcome.readCount += readCount
+		if isFull {
+			break
+		}
+	}
+	err := state.EndPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size == 4 {
+		outcome.value = uint64(state.AsUint())
+	} else {
+		outcome.value = state.AsHyperUint()
+	}
+	return
+}
+
+func TestPrimitiveWholeUpdateMatchesBuffered(t *testing.T) {
+	data := []byte{0x80, 0x01, 0x02, 0x03, 0xfc, 0xfd, 0xfe, 0xff, 0x55, 0xaa}
+	for _, size := range []int{4, 8} {
+		whole := decodePrimitive(t, size, data)
+		if whole.readCount != size || len(whole.events) != 1 {
+			t.Fatalf("size %d: whole update read %d bytes with %d events", size, whole.readCount, len(whole.events))
+		}
+		for split := 0; split < size; split++ {
+			buffered := decodePrimitive(t, size, data[:split], data[split:])
+			if buffered.value != whole.value || buffered.readCount != whole.readCount {
+				t.Fatalf(
+					"size %d, split %d: got value %x after %d bytes, want %x after %d",
+					size,
+					split,
+					buffered.value,
+					buffered.readCount,
+					whole.value,
+					whole.readCount,
+				)
+			}
+			if len(buffered.events) != 1 {
+				t.Fatalf("size %d, split %d: got %d events", size, split, len(buffered.events))
+			}
+			expected, actual := whole.events[0], buffered.events[0]
+			if actual.Kind != expected.Kind || actual.Name != expected.Name || actual.Offset != expected.Offset ||
+					!bytes.Equal(actual.Bytes, expected.Bytes) {
+				t.Fatalf("size %d, split %d: got event %+v, want %+v", size, split, actual, expected)
+			}
+		}
+	}
+}
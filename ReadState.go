@@ -14,6 +14,20 @@ type RequestReadState interface {
 	ResponsePacket() Packet
 }
 
+type ContextualReadState interface {
+	ReadState
+	InheritDecodeContext(*DecodeContext)
+}
+
+func passDecodeContext(handler ReadState, context *DecodeContext) {
+	if context == nil {
+		return
+	}
+	if contextual, ok := handler.(ContextualReadState); ok {
+		contextual.InheritDecodeContext(context)
+	}
+}
+
 type ReadStateFactory func(uint32, uint32) (ReadState, error)
 
 type TypedReadState[T any] interface {
@@ -32,3 +46,7 @@ func TypedReadStateFactoryOf[T any](factory ReadStateFactory) TypedReadStateFact
 		return
 	}
 }
+
+func VoidArm[T any](uint32, uint32) (TypedReadState[T], error) {
+	return TheEmptyReadState, nil
+}
@@ -0,0 +1,91 @@
+package goxdr
+
+import (
+	"io"
+)
+
+type StreamDecoder struct {
+	Reader io.Reader
+	RejectTrailingData bool
+	ProbeTrailingData bool
+	buffer []byte
+	start int
+	end int
+}
+
+func NewStreamDecoder(reader io.Reader, bufferSize int) *StreamDecoder {
+	if bufferSize < minStreamBufferSize {
+		bufferSize = minStreamBufferSize
+	}
+	return &StreamDecoder {
+		Reader: reader,
+		buffer: make([]byte, bufferSize),
+	}
+}
+
+func(decoder *StreamDecoder) Buffered() int {
+	return decoder.end - decoder.start
+}
+
+func(decoder *StreamDecoder) probe() error {
+	for {
+		fillCount, readErr := decoder.Reader.Read(decoder.buffer)
+		decoder.start = 0
+		decoder.end = fillCount
+		if fillCount > 0 || readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+func(decoder *StreamDecoder) Decode(state ReadState) (err error) {
+	if len(decoder.buffer) == 0 {
+		decoder.buffer = make([]byte, minStreamBufferSize)
+	}
+	var consumed int
+	var readErr error
+	for {
+		offered := decoder.end - decoder.start
+		readCount, isFull := state.Update(decoder.buffer[decoder.start:decoder.end])
+		if readCount < 0 || readCount > offered {
+			err = &OverreadError {
+				Subject: "Root read state",
+				ReadCount: readCount,
+				Offered: offered,
+			}
+			return
+		}
+		decoder.start += readCount
+		consumed += readCount
+		if isFull || decoder.start < decoder.end || readErr != nil {
+			break
+		}
+		var fillCount int
+		fillCount, readErr = decoder.Reader.Read(decoder.buffer)
+		decoder.start = 0
+		decoder.end = fillCount
+		if readErr != nil && readErr != io.EOF {
+			err = readErr
+			return
+		}
+		if fillCount == 0 && readErr == io.EOF {
+			if consumed == 0 {
+				err = io.EOF
+				return
+			}
+			break
+		}
+	}
+	err = state.EndPacket()
+	if err == nil && decoder.RejectTrailingData && decoder.ProbeTrailingData && decoder.start == decoder.end && readErr == nil {
+		err = decoder.probe()
+	}
+	if err == nil && decoder.RejectTrailingData && decoder.start < decoder.end {
+		err = NewTrailingDataError(decoder.buffer[decoder.start:decoder.end])
+		decoder.start = decoder.end
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package goxdr
+
+import (
+	"io"
+	"time"
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamDecoderRejectsUnreadTrailingData(t *testing.T) {
+	data := []byte{0, 0, 0, 1, 0xAB}
+	decoder := NewStreamDecoder(iotest.OneByteReader(bytes.NewReader(data)), 0)
+	decoder.RejectTrailingData = true
+	decoder.ProbeTrailingData = true
+	state, _ := NewPrimitiveReadState(4)
+	var trailingError *TrailingDataError
+	if err := decoder.Decode(state); !errors.As(err, &trailingError) {
+		t.Fatalf("Decode = %v, want trailing data error", err)
+	}
+	if len(trailingError.Preview) != 1 || trailingError.Preview[0] != 0xAB {
+		t.Fatalf("Preview = %x, want ab", trailingError.Preview)
+	}
+}
+
+func TestStreamDecoderAcceptsExactStream(t *testing.T) {
+	decoder := NewStreamDecoder(iotest.OneByteReader(bytes.NewReader([]byte{0, 0, 0, 1})), 0)
+	decoder.RejectTrailingData = true
+	decoder.ProbeTrailingData = true
+	state, _ := NewPrimitiveReadState(4)
+	if err := decoder.Decode(state); err != nil {
+		t.Fatalf("Decode = %v, want success", err)
+	}
+	if state.AsUint() != 1 {
+		t.Fatalf("AsUint = %d, want 1", state.AsUint())
+	}
+}
+
+func TestStreamDecoderDoesNotReadPastMessage(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	go writer.Write([]byte{0, 0, 0, 1})
+	decoder := NewStreamDecoder(reader, 0)
+	decoder.RejectTrailingData = true
+	state, _ := NewPrimitiveReadState(4)
+	done := make(chan error, 1)
+	go func() {
+		done <- decoder.Decode(state)
+	}()
+	select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Decode = %v, want success", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Decode blocked reading past the end of the message")
+	}
+}
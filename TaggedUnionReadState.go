@@ -1,92 +1,188 @@
 package goxdr
 
-import (
-	"fmt"
-	"errors"
-)
-
 type TaggedUnionReadState[T any] struct {
 	PrimitiveState *PrimitiveReadState
 	HandlerFactory TypedReadStateFactory[T]
+	Arms map[uint32]TypedReadStateFactory[T]
+	DefaultArm TypedReadStateFactory[T]
 	HandlerName string
+	SignedDiscriminant bool
+	ArmNames map[uint32]string
+	DecodeContext *DecodeContext
 	currentHandler ReadState
+	ended bool
 	firstError error
 }
 
 func(state *TaggedUnionReadState[T]) Reset() {
 	state.PrimitiveState.Reset(4)
 	state.currentHandler = nil
+	state.ended = false
 	state.firstError = nil
 }
 
+func(state *TaggedUnionReadState[T]) InheritDecodeContext(context *DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *TaggedUnionReadState[T]) inheritDecodeContext() {
+	state.PrimitiveState.quiet = true
+	if state.DecodeContext != nil && state.PrimitiveState.DecodeContext == nil {
+		state.PrimitiveState.DecodeContext = state.DecodeContext
+	}
+}
+
+func(state *TaggedUnionReadState[T]) fail(err error, offset uint64) {
+	state.firstError = WrapDecodeError(err, state.HandlerName, offset)
+}
+
+func(state *TaggedUnionReadState[T]) armOffset() uint64 {
+	return uint64(state.PrimitiveState.primitiveSize)
+}
+
+func(state *TaggedUnionReadState[T]) createArm(discriminant uint32) (handler ReadState, err error) {
+	if factory, found := state.Arms[discriminant]; found {
+		if factory == nil {
+			return nil, &NilArmFactoryError {
+				Discriminant: discriminant,
+				Signed: state.SignedDiscriminant,
+			}
+		}
+		return factory(discriminant, 0)
+	}
+	if state.HandlerFactory != nil {
+		handler, err = state.HandlerFactory(discriminant, 0)
+		if err != nil || handler != nil {
+			return
+		}
+	}
+	if state.DefaultArm != nil {
+		handler, err = state.DefaultArm(discriminant, 0)
+	}
+	return
+}
+
 func(state *TaggedUnionReadState[T]) enterArm() bool {
 	discriminant := state.PrimitiveState.AsUint()
-	state.currentHandler, state.firstError = state.HandlerFactory(discriminant, 0)
-	if state.firstError != nil {
-		return true
-	}
-	if state.currentHandler == nil {
-		state.firstError = &UnionDiscriminantError {
+	var err error
+	state.currentHandler, err = state.createArm(discriminant)
+	if err == nil && state.currentHandler == nil {
+		err = &UnionDiscriminantError {
 			Discriminant: discriminant,
 			HandlerName: state.HandlerName,
+			Signed: state.SignedDiscriminant,
 		}
+	}
+	if err != nil {
+		state.currentHandler = nil
+		state.fail(err, 0)
 		return true
 	}
+	passDecodeContext(state.currentHandler, state.DecodeContext)
+	if state.DecodeContext.Observing() {
+		state.DecodeContext.Observe(&DecodeEvent {
+			Kind: EventBeginUnion,
+			Name: state.HandlerName,
+			Offset: state.DecodeContext.ByteCount() - state.armOffset(),
+			Discriminant: discriminant,
+			Signed: state.SignedDiscriminant,
+			Arm: state.ArmNames[discriminant],
+		})
+	}
 	return false
 }
 
+func(state *TaggedUnionReadState[T]) endArm() {
+	state.firstError = WrapDecodeError(state.currentHandler.EndPacket(), state.HandlerName, state.armOffset())
+	if state.firstError == nil && state.DecodeContext.Observing() {
+		state.DecodeContext.Observe(&DecodeEvent {
+			Kind: EventEndUnion,
+			Name: state.HandlerName,
+			Offset: state.DecodeContext.ByteCount(),
+		})
+	}
+}
+
 func(state *TaggedUnionReadState[T]) Update(bytes []byte) (readCount int, isFull bool) {
-	if state.firstError != nil {
+	if state.firstError != nil || state.ended {
 		isFull = true
 		return
 	}
+	state.inheritDecodeContext()
+	err := state.DecodeContext.Enter()
+	if err != nil {
+		state.fail(err, 0)
+		isFull = true
+		return
+	}
+	defer state.DecodeContext.Leave()
 	length := len(bytes)
 	if state.currentHandler == nil {
 		readCount, isFull = state.PrimitiveState.Update(bytes)
 		if readCount > length {
-			state.firstError = errors.New(fmt.Sprintf(
-				"Primitive read state read %d bytes, but was supposed to only read %d",
-				readCount,
-				length,
-			))
+			state.fail(&OverreadError {
+				Subject: "Primitive read state",
+				ReadCount: readCount,
+				Offered: length,
+			}, 0)
 			isFull = true
 			return
 		}
 		if !isFull {
 			return
 		}
-		state.firstError = state.PrimitiveState.EndPacket()
-		if state.firstError != nil || state.enterArm() {
+		err = state.PrimitiveState.EndPacket()
+		if err != nil {
+			state.fail(err, 0)
+			return
+		}
+		if state.enterArm() {
 			return
 		}
 	}
 	var armReadCount int
 	armReadCount, isFull = state.currentHandler.Update(bytes[readCount:])
 	if armReadCount > length - readCount {
-		state.firstError = errors.New(fmt.Sprintf(
-			"Tagged union arm read state read %d bytes, but was supposed to only read %d",
-			armReadCount,
-			length - readCount,
-		))
+		state.fail(&OverreadError {
+			Subject: "Tagged union arm read state",
+			ReadCount: armReadCount,
+			Offered: length - readCount,
+		}, state.armOffset())
 		isFull = true
 		return
 	}
 	readCount += armReadCount
+	if isFull {
+		state.endArm()
+		state.ended = true
+	}
 	return
 }
 
 func(state *TaggedUnionReadState[T]) EndPacket() error {
-	if state.firstError == nil {
-		if state.currentHandler != nil {
-			state.firstError = state.currentHandler.EndPacket()
-		} else {
-			state.firstError = state.PrimitiveState.EndPacket()
-			if state.firstError == nil && !state.enterArm() {
-				state.firstError = state.currentHandler.EndPacket()
+	if state.firstError == nil && !state.ended {
+		state.inheritDecodeContext()
+		err := state.DecodeContext.Enter()
+		if err != nil {
+			state.fail(err, 0)
+			return state.firstError
+		}
+		defer state.DecodeContext.Leave()
+		if state.currentHandler == nil {
+			err = state.PrimitiveState.EndPacket()
+			if err != nil {
+				state.fail(err, 0)
+				return state.firstError
+			}
+			if state.enterArm() {
+				return state.firstError
 			}
 		}
+		state.endArm()
 	}
 	return state.firstError
 }
 
-var _ ReadState = &TaggedUnionReadState[int]{}
+var _ ContextualReadState = &TaggedUnionReadState[int]{}
@@ -0,0 +1,55 @@
+package goxdr
+
+type UnionDiscriminant interface {
+	~int32 | ~uint32
+}
+
+func isSignedDiscriminant[D UnionDiscriminant]() bool {
+	var probe D
+	probe--
+	return probe < 0
+}
+
+type TypedUnionReadState[T any, D UnionDiscriminant] struct {
+	TaggedUnionReadState[T]
+}
+
+func NewTypedUnionReadState[T any, D UnionDiscriminant](
+	handlerName string,
+	factory func(D) (TypedReadState[T], error),
+) *TypedUnionReadState[T, D] {
+	primitiveState, _ := NewPrimitiveReadState(4)
+	state := &TypedUnionReadState[T, D] {
+		TaggedUnionReadState: TaggedUnionReadState[T] {
+			PrimitiveState: primitiveState,
+			HandlerName: handlerName,
+			SignedDiscriminant: isSignedDiscriminant[D](),
+		},
+	}
+	if factory != nil {
+		state.HandlerFactory = func(discriminant uint32, _ uint32) (TypedReadState[T], error) {
+			return factory(D(discriminant))
+		}
+	}
+	return state
+}
+
+func(state *TypedUnionReadState[T, D]) SetArm(discriminant D, factory TypedReadStateFactory[T]) {
+	if state.Arms == nil {
+		state.Arms = make(map[uint32]TypedReadStateFactory[T])
+	}
+	state.Arms[uint32(discriminant)] = factory
+}
+
+func(state *TypedUnionReadState[T, D]) SetArmName(discriminant D, name string) {
+	if state.ArmNames == nil {
+		state.ArmNames = make(map[uint32]string)
+	}
+	state.ArmNames[uint32(discriminant)] = name
+}
+
+func(state *TypedUnionReadState[T, D]) Discriminant() D {
+	return D(state.PrimitiveState.AsUint())
+}
+
+var _ ContextualReadState = &TypedUnionReadState[int, int32]{}
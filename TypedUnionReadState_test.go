@@ -0,0 +1,76 @@
+package goxdr
+
+import (
+	"errors"
+	"testing"
+)
+
+type shape int32
+
+const (
+	shapeNone shape = -1
+	shapeCircle shape = 1
+	shapeSquare shape = 2
+)
+
+func newShapeState() *TypedUnionReadState[uint32, shape] {
+	state := NewTypedUnionReadState[uint32, shape]("shape", nil)
+	state.SetArm(shapeNone, VoidArm[uint32])
+	state.SetArm(shapeCircle, primitiveFactory)
+	state.DefaultArm = func(uint32, uint32) (TypedReadState[uint32], error) {
+		return NewPrimitiveReadState(8)
+	}
+	return state
+}
+
+func TestTypedUnionArmsAndDefault(t *testing.T) {
+	cases := []struct {
+		data []byte
+		discriminant shape
+		armSize int
+	}{
+		{[]byte{0xFF, 0xFF, 0xFF, 0xFF}, shapeNone, 0},
+		{[]byte{0, 0, 0, 1, 0, 0, 0, 7}, shapeCircle, 4},
+		{[]byte{0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 7}, shapeSquare, 8},
+	}
+	for _, testCase := range cases {
+		for split := 0; split <= len(testCase.data); split++ {
+			state := newShapeState()
+			first, _ := state.Update(testCase.data[:split])
+			second, _ := state.Update(testCase.data[first:])
+			if first + second != len(testCase.data) {
+				t.Fatalf("%d split at %d: consumed %d of %d", testCase.discriminant, split, first + second, len(testCase.data))
+			}
+			if err := state.EndPacket(); err != nil {
+				t.Fatalf("%d split at %d: %v", testCase.discriminant, split, err)
+			}
+			if state.Discriminant() != testCase.discriminant {
+				t.Fatalf("Discriminant = %d, want %d", state.Discriminant(), testCase.discriminant)
+			}
+		}
+	}
+}
+
+func TestTypedUnionNilArmFactory(t *testing.T) {
+	state := newShapeState()
+	state.SetArm(shapeSquare, nil)
+	state.Update([]byte{0, 0, 0, 2})
+	err := state.EndPacket()
+	var factoryError *NilArmFactoryError
+	if !errors.Is(err, ErrNilReadState) || !errors.As(err, &factoryError) || factoryError.Discriminant != 2 {
+		t.Fatalf("EndPacket = %v, want nil arm factory error for discriminant 2", err)
+	}
+	var discriminantError *UnionDiscriminantError
+	if errors.As(err, &discriminantError) {
+		t.Fatalf("nil arm factory fell through to %v", err)
+	}
+}
+
+func TestTypedUnionUnknownSignedDiscriminant(t *testing.T) {
+	state := NewTypedUnionReadState[uint32, shape]("shape", nil)
+	state.Update([]byte{0xFF, 0xFF, 0xFF, 0xFE})
+	var discriminantError *UnionDiscriminantError
+	if err := state.EndPacket(); !errors.As(err, &discriminantError) || !discriminantError.Signed {
+		t.Fatalf("EndPacket = %v, want signed discriminant error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package goxdr
+
+type UnionCases struct {
+	Cases []uint32
+	HasDefault bool
+	HandlerName string
+	Signed bool
+}
+
+var AnyCase = &UnionCases {
+	HasDefault: true,
+}
+
+func(cases *UnionCases) Accepts(discriminant uint32) bool {
+	if cases == nil {
+		return false
+	}
+	if cases.HasDefault {
+		return true
+	}
+	for _, value := range cases.Cases {
+		if value == discriminant {
+			return true
+		}
+	}
+	return false
+}
+
+func(cases *UnionCases) Check(discriminant uint32) error {
+	if cases == nil {
+		return ErrNoUnionCases
+	}
+	if cases.Accepts(discriminant) {
+		return nil
+	}
+	return &UnionDiscriminantError {
+		Discriminant: discriminant,
+		HandlerName: cases.HandlerName,
+		Signed: cases.Signed,
+	}
+}
@@ -0,0 +1,37 @@
+package goxdr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestUnionPacketRequiresCases(t *testing.T) {
+	arm := ByteSlicePacket {
+		Bytes: []byte{0, 0, 0, 1},
+	}
+	var out bytes.Buffer
+	err := (&UnionPacket {
+		Discriminant: 3,
+		Arm: arm,
+	}).WriteTo(make([]byte, 8), &out)
+	if !errors.Is(err, ErrNoUnionCases) || out.Len() != 0 {
+		t.Fatalf("WriteTo without cases = %v after %d bytes, want ErrNoUnionCases", err, out.Len())
+	}
+	_, err = (&UnionPacket {
+		Discriminant: 3,
+		Arm: arm,
+	}).AppendTo(nil)
+	if !errors.Is(err, ErrNoUnionCases) {
+		t.Fatalf("AppendTo without cases = %v, want ErrNoUnionCases", err)
+	}
+	err = WriteUnion(3, arm, AnyCase, make([]byte, 8), &out)
+	if err != nil || out.Len() != 8 {
+		t.Fatalf("WriteUnion with AnyCase = %v after %d bytes", err, out.Len())
+	}
+	var discriminantError *UnionDiscriminantError
+	err = WriteUnion(3, arm, &UnionCases{Cases: []uint32{1, 2}}, make([]byte, 8), &out)
+	if !errors.As(err, &discriminantError) {
+		t.Fatalf("WriteUnion with undeclared case = %v", err)
+	}
+}
@@ -1,93 +1,120 @@
 package goxdr
 
-import (
-	"fmt"
-	"errors"
-)
-
 type VariableLengthOpaqueReadState struct {
 	PrimitiveState *PrimitiveReadState
 	FixedLengthState *FixedLengthOpaqueReadState
 	MaxLength uint32
+	DecodeContext *DecodeContext
 	inBody bool
+	offset uint64
+	bodyOffset uint64
 	firstError error
 }
 
 func(state *VariableLengthOpaqueReadState) Reset() {
 	state.PrimitiveState.Reset(4)
 	state.inBody = false
+	state.offset = 0
+	state.bodyOffset = 0
 	state.firstError = nil
 }
 
+func(state *VariableLengthOpaqueReadState) InheritDecodeContext(context *DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *VariableLengthOpaqueReadState) inheritDecodeContext() {
+	state.PrimitiveState.quiet = true
+	if state.DecodeContext != nil {
+		if state.PrimitiveState.DecodeContext == nil {
+			state.PrimitiveState.DecodeContext = state.DecodeContext
+		}
+		if state.FixedLengthState.DecodeContext == nil {
+			state.FixedLengthState.DecodeContext = state.DecodeContext
+		}
+	}
+}
+
+func(state *VariableLengthOpaqueReadState) fail(err error, offset uint64) {
+	state.firstError = WrapDecodeError(err, state.FixedLengthState.HandlerName, offset)
+}
+
+func(state *VariableLengthOpaqueReadState) enterBody() {
+	state.FixedLengthState.Reset()
+	state.FixedLengthState.ExpectedLength = state.PrimitiveState.AsUint()
+	state.inBody = true
+	state.offset = uint64(state.PrimitiveState.primitiveSize)
+	state.bodyOffset = state.offset
+}
 
 func(state *VariableLengthOpaqueReadState) Update(bytes []byte) (readCount int, isFull bool) {
 	if state.firstError != nil {
 		isFull = true
 		return
 	}
+	state.inheritDecodeContext()
 	length := len(bytes)
 	if !state.inBody {
 		readCount, isFull = state.PrimitiveState.Update(bytes)
 		if readCount > length {
-			state.firstError = errors.New(fmt.Sprintf(
-				"Primitive read state read %d bytes, but was supposed to only read %d",
-				readCount,
-				length,
-			))
+			state.fail(&OverreadError {
+				Subject: "Primitive read state",
+				ReadCount: readCount,
+				Offered: length,
+			}, 0)
 			isFull = true
 			return
 		}
 		if !isFull {
 			return
 		}
-		state.firstError = state.PrimitiveState.EndPacket()
-		if state.firstError != nil {
-			isFull = true
+		err := state.PrimitiveState.EndPacket()
+		if err != nil {
+			state.fail(err, 0)
 			return
 		}
-		state.FixedLengthState.Reset()
-		state.FixedLengthState.ExpectedLength = state.PrimitiveState.AsUint()
-		if state.FixedLengthState.ExpectedLength > state.MaxLength {
-			state.firstError = errors.New(fmt.Sprintf(
-				"Variable-length opaque data has maximum length %d, but encountered length %d",
-				state.MaxLength,
-				state.FixedLengthState.ExpectedLength,
-			))
-			isFull = true
+		if state.PrimitiveState.AsUint() > state.MaxLength {
+			state.fail(&MaxLengthError {
+				Subject: "Variable-length opaque data",
+				Maximum: state.MaxLength,
+				Actual: state.PrimitiveState.AsUint(),
+			}, 0)
 			return
 		}
-		state.inBody = true
+		state.enterBody()
 	}
 	var bodyReadCount int
 	bodyReadCount, isFull = state.FixedLengthState.Update(bytes[readCount:])
 	if bodyReadCount > length - readCount {
-		state.firstError = errors.New(fmt.Sprintf(
-			"Fixed length opaque data read state read %d bytes, but was supposed to only read %d",
-			bodyReadCount,
-			length - readCount,
-		))
+		state.fail(&OverreadError {
+			Subject: "Fixed length opaque data read state",
+			ReadCount: bodyReadCount,
+			Offered: length - readCount,
+		}, state.offset)
 		isFull = true
 		return
 	}
 	readCount += bodyReadCount
+	state.offset += uint64(bodyReadCount)
 	return
 }
 
 func(state *VariableLengthOpaqueReadState) EndPacket() error {
 	if state.firstError == nil {
-		if state.inBody {
-			state.firstError = state.FixedLengthState.EndPacket()
-		} else {
-			state.firstError = state.PrimitiveState.EndPacket()
-			if state.firstError == nil {
-				state.FixedLengthState.Reset()
-				state.FixedLengthState.ExpectedLength = state.PrimitiveState.AsUint()
-				state.inBody = true
-				state.firstError = state.FixedLengthState.EndPacket()
+		state.inheritDecodeContext()
+		if !state.inBody {
+			err := state.PrimitiveState.EndPacket()
+			if err != nil {
+				state.fail(err, 0)
+				return state.firstError
 			}
+			state.enterBody()
 		}
+		state.firstError = WrapDecodeError(state.FixedLengthState.EndPacket(), "", state.bodyOffset)
 	}
 	return state.firstError
 }
 
-var _ ReadState = &VariableLengthOpaqueReadState{}
+var _ ContextualReadState = &VariableLengthOpaqueReadState{}
@@ -0,0 +1,92 @@
+package goxdr
+
+import (
+	"io"
+	"hash"
+)
+
+type WriterReadState struct {
+	Writer io.Writer
+	offset uint64
+	firstError error
+}
+
+func NewWriterReadState(writer io.Writer) *WriterReadState {
+	return &WriterReadState {
+		Writer: writer,
+	}
+}
+
+func(state *WriterReadState) Reset() {
+	state.offset = 0
+	state.firstError = nil
+}
+
+func(state *WriterReadState) Offset() uint64 {
+	return state.offset
+}
+
+func(state *WriterReadState) Update(bytes []byte) (readCount int, isFull bool) {
+	if state.firstError != nil {
+		isFull = true
+		return
+	}
+	if len(bytes) == 0 {
+		return
+	}
+	readCount, err := state.Writer.Write(bytes)
+	if readCount < 0 || readCount > len(bytes) {
+		readCount = 0
+	}
+	if err == nil && readCount < len(bytes) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		state.firstError = err
+		isFull = true
+	}
+	state.offset += uint64(readCount)
+	return
+}
+
+func(state *WriterReadState) EndPacket() error {
+	return state.firstError
+}
+
+var _ ReadState = &WriterReadState{}
+
+type HashReadState struct {
+	Hash hash.Hash
+	offset uint64
+}
+
+func NewHashReadState(digest hash.Hash) *HashReadState {
+	return &HashReadState {
+		Hash: digest,
+	}
+}
+
+func(state *HashReadState) Reset() {
+	state.Hash.Reset()
+	state.offset = 0
+}
+
+func(state *HashReadState) Offset() uint64 {
+	return state.offset
+}
+
+func(state *HashReadState) Update(bytes []byte) (readCount int, isFull bool) {
+	readCount, _ = state.Hash.Write(bytes)
+	state.offset += uint64(readCount)
+	return
+}
+
+func(state *HashReadState) EndPacket() error {
+	return nil
+}
+
+func(state *HashReadState) Sum(bytes []byte) []byte {
+	return state.Hash.Sum(bytes)
+}
+
+var _ ReadState = &HashReadState{}
@@ -0,0 +1,99 @@
+package goxdr
+
+import (
+	"io"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"crypto/sha256"
+)
+
+var errSinkFull = errors.New("sink full")
+
+type cappedWriter struct {
+	capacity int
+	written bytes.Buffer
+	failure error
+}
+
+func(writer *cappedWriter) Write(bytes []byte) (int, error) {
+	room := writer.capacity - writer.written.Len()
+	if len(bytes) <= room {
+		return writer.written.Write(bytes)
+	}
+	writer.written.Write(bytes[0:room])
+	return room, writer.failure
+}
+
+func TestWriterReadStateReportsFailedWrite(t *testing.T) {
+	for _, failure := range []error{errSinkFull, nil} {
+		writer := &cappedWriter {
+			capacity: 3,
+			failure: failure,
+		}
+		err := DecodeExact(&FixedLengthOpaqueReadState {
+			ExpectedLength: 7,
+			Handler: NewWriterReadState(writer),
+			HandlerName: "payload",
+		}, []byte("abcdefg\x00"))
+		expected := failure
+		if expected == nil {
+			expected = io.ErrShortWrite
+		}
+		var handlerError *OpaqueHandlerError
+		if !errors.Is(err, expected) || !errors.As(err, &handlerError) {
+			t.Fatalf("DecodeExact = %v, want %v from the handler", err, expected)
+		}
+		if handlerError.Offset != 3 || writer.written.String() != "abc" {
+			t.Fatalf("failed at offset %d after writing %q, want offset 3", handlerError.Offset, writer.written.String())
+		}
+		if count := strings.Count(err.Error(), "payload"); count != 1 {
+			t.Fatalf("error %q names the handler %d times, want once", err, count)
+		}
+	}
+}
+
+func TestWriterReadStateStopsAfterFailure(t *testing.T) {
+	writer := &cappedWriter {
+		capacity: 2,
+		failure: errSinkFull,
+	}
+	state := NewWriterReadState(writer)
+	readCount, isFull := state.Update([]byte("abcd"))
+	if readCount != 2 || !isFull {
+		t.Fatalf("Update = %d, %t, want 2, true", readCount, isFull)
+	}
+	readCount, isFull = state.Update([]byte("ef"))
+	if readCount != 0 || !isFull || state.Offset() != 2 {
+		t.Fatalf("Update after failure = %d, %t at offset %d", readCount, isFull, state.Offset())
+	}
+	if err := state.EndPacket(); err != errSinkFull {
+		t.Fatalf("EndPacket = %v, want the write error", err)
+	}
+	state.Reset()
+	if state.Offset() != 0 || state.EndPacket() != nil {
+		t.Fatal("Reset kept the failure")
+	}
+}
+
+func TestHashReadState(t *testing.T) {
+	data := "sillyprog"
+	state := NewHashReadState(sha256.New())
+	err := DecodeExact(&FixedLengthOpaqueReadState {
+		ExpectedLength: uint32(len(data)),
+		Handler: state,
+	}, []byte(data + "\x00\x00\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := sha256.Sum256([]byte(data))
+	if !bytes.Equal(state.Sum(nil), expected[:]) || state.Offset() != uint64(len(data)) {
+		t.Fatalf("Sum = %x after %d bytes, want %x", state.Sum(nil), state.Offset(), expected)
+	}
+	state.Reset()
+	empty := sha256.Sum256(nil)
+	if !bytes.Equal(state.Sum(nil), empty[:]) || state.Offset() != 0 {
+		t.Fatal("Reset kept hashed data")
+	}
+}
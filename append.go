@@ -0,0 +1,207 @@
+package goxdr
+
+import (
+	"sync"
+	"math"
+	"encoding/binary"
+)
+
+type AppendablePacket interface {
+	Packet
+	AppendTo([]byte) ([]byte, error)
+}
+
+type sliceWriter struct {
+	bytes []byte
+	scratch [minScratchBufferSize]byte
+}
+
+var sliceWriterPool = sync.Pool {
+	New: func() any {
+		return &sliceWriter{}
+	},
+}
+
+func(appender *sliceWriter) Write(bytes []byte) (int, error) {
+	appender.bytes = append(appender.bytes, bytes...)
+	return len(bytes), nil
+}
+
+func appendByWriting(dst []byte, packet Packet) ([]byte, error) {
+	appender := sliceWriterPool.Get().(*sliceWriter)
+	appender.bytes = dst
+	err := packet.WriteTo(appender.scratch[:], appender)
+	extended := appender.bytes
+	appender.bytes = nil
+	sliceWriterPool.Put(appender)
+	if err != nil {
+		return dst, err
+	}
+	return extended, nil
+}
+
+func AppendPacket(dst []byte, packet Packet) ([]byte, error) {
+	if appendable, ok := packet.(AppendablePacket); ok {
+		return appendable.AppendTo(dst)
+	}
+	return appendByWriting(dst, packet)
+}
+
+func AppendInt(dst []byte, value int32) []byte {
+	return binary.BigEndian.AppendUint32(dst, uint32(value))
+}
+
+func AppendUint(dst []byte, value uint32) []byte {
+	return binary.BigEndian.AppendUint32(dst, value)
+}
+
+func AppendHyperInt(dst []byte, value int64) []byte {
+	return binary.BigEndian.AppendUint64(dst, uint64(value))
+}
+
+func AppendHyperUint(dst []byte, value uint64) []byte {
+	return binary.BigEndian.AppendUint64(dst, value)
+}
+
+func AppendFloat(dst []byte, value float32) []byte {
+	return binary.BigEndian.AppendUint32(dst, math.Float32bits(value))
+}
+
+func AppendDouble(dst []byte, value float64) []byte {
+	return binary.BigEndian.AppendUint64(dst, math.Float64bits(value))
+}
+
+func AppendBool(dst []byte, value bool) []byte {
+	if value {
+		return AppendUint(dst, 1)
+	}
+	return AppendUint(dst, 0)
+}
+
+func appendPadding(dst []byte, length int) []byte {
+	if remainder := length % 4; remainder > 0 {
+		dst = append(dst, zeroBytesSlice[0:4 - remainder]...)
+	}
+	return dst
+}
+
+func AppendFixedOpaque(dst []byte, bytes []byte) []byte {
+	return appendPadding(append(dst, bytes...), len(bytes))
+}
+
+func AppendVarOpaque(dst []byte, bytes []byte, maxSize uint32) ([]byte, error) {
+	if uint64(len(bytes)) > uint64(maxSize) {
+		actualSize := uint32(math.MaxUint32)
+		if uint64(len(bytes)) < uint64(actualSize) {
+			actualSize = uint32(len(bytes))
+		}
+		return dst, &MaxLengthError {
+			Subject: "Packet",
+			Maximum: maxSize,
+			Actual: actualSize,
+		}
+	}
+	dst = AppendUint(dst, uint32(len(bytes)))
+	return AppendFixedOpaque(dst, bytes), nil
+}
+
+func AppendString(dst []byte, text string, maxSize uint32) ([]byte, error) {
+	if uint64(len(text)) > uint64(maxSize) {
+		actualSize := uint32(math.MaxUint32)
+		if uint64(len(text)) < uint64(actualSize) {
+			actualSize = uint32(len(text))
+		}
+		return dst, &MaxLengthError {
+			Subject: "String",
+			Maximum: maxSize,
+			Actual: actualSize,
+		}
+	}
+	dst = AppendUint(dst, uint32(len(text)))
+	return appendPadding(append(dst, text...), len(text)), nil
+}
+
+func(packet ByteSlicePacket) AppendTo(dst []byte) ([]byte, error) {
+	return append(dst, packet.Bytes...), nil
+}
+
+func(packet *PaddingPacket) AppendTo(dst []byte) (extended []byte, err error) {
+	shortLength := packet.ShortPacket.ByteSize()
+	if shortLength > packet.RequiredLength || packet.Padding != nil {
+		return appendByWriting(dst, packet)
+	}
+	extended, err = AppendPacket(dst, packet.ShortPacket)
+	if err != nil {
+		return
+	}
+	for remainder := packet.RequiredLength - shortLength; remainder > 0; {
+		chunk := remainder
+		if chunk > uint32(zeroSliceSize) {
+			chunk = uint32(zeroSliceSize)
+		}
+		extended = append(extended, zeroBytesSlice[0:chunk]...)
+		remainder -= chunk
+	}
+	return
+}
+
+func(packet *UnionPacket) AppendTo(dst []byte) (extended []byte, err error) {
+	err = packet.Cases.Check(packet.Discriminant)
+	if err != nil {
+		return dst, err
+	}
+	extended = AppendUint(dst, packet.Discriminant)
+	if packet.Arm != nil {
+		extended, err = AppendPacket(extended, packet.Arm)
+	}
+	return
+}
+
+func appendGenerated(dst []byte, generator ByteGenerator, expectedSize uint32, padding BytePadder) ([]byte, error) {
+	appender := sliceWriterPool.Get().(*sliceWriter)
+	appender.bytes = dst
+	err := generator(appender.scratch[:], appender)
+	if err == nil {
+		generated := uint64(len(appender.bytes) - len(dst))
+		err = finishOpaqueStream(generated, expectedSize, appender.scratch[:], appender, padding, nil)
+	}
+	extended := appender.bytes
+	appender.bytes = nil
+	sliceWriterPool.Put(appender)
+	if err != nil {
+		return dst, err
+	}
+	return extended, nil
+}
+
+func(packet *FixedLengthOpaqueGeneratorPacket) AppendTo(dst []byte) ([]byte, error) {
+	_, err := MeasureFixedLengthOpaqueGenerator(packet.ExpectedSize)
+	if err != nil {
+		return dst, err
+	}
+	return appendGenerated(dst, packet.Generator, packet.ExpectedSize, packet.Padding)
+}
+
+func(packet *VariableLengthOpaqueGeneratorPacket) AppendTo(dst []byte) ([]byte, error) {
+	_, err := MeasureVariableLengthOpaqueGenerator(packet.ExpectedSize, packet.MaxSize)
+	if err != nil {
+		return dst, err
+	}
+	return appendGenerated(AppendUint(dst, packet.ExpectedSize), packet.Generator, packet.ExpectedSize, packet.Padding)
+}
+
+func(packet *FixedLengthArrayGeneratorPacket[T]) AppendTo(dst []byte) ([]byte, error) {
+	return appendByWriting(dst, packet)
+}
+
+func(packet *VariableLengthArrayGeneratorPacket[T]) AppendTo(dst []byte) ([]byte, error) {
+	return appendByWriting(dst, packet)
+}
+
+var _ AppendablePacket = ByteSlicePacket{}
+var _ AppendablePacket = &PaddingPacket{}
+var _ AppendablePacket = &UnionPacket{}
+var _ AppendablePacket = &FixedLengthOpaqueGeneratorPacket{}
+var _ AppendablePacket = &VariableLengthOpaqueGeneratorPacket{}
+var _ AppendablePacket = &FixedLengthArrayGeneratorPacket[int]{}
+var _ AppendablePacket = &VariableLengthArrayGeneratorPacket[int]{}
@@ -0,0 +1,191 @@
+package goxdr
+
+import (
+	"io"
+	"math"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func writtenBytes(t *testing.T, write func([]byte, io.Writer) error) []byte {
+	var out bytes.Buffer
+	err := write(make([]byte, 8), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestAppendPrimitivesMatchWriters(t *testing.T) {
+	prefix := []byte{0xEE}
+	cases := []struct {
+		name string
+		appended []byte
+		write func([]byte, io.Writer) error
+	} {
+		{"int", AppendInt(prefix, -2), func(buffer []byte, writer io.Writer) error {
+			return WriteInt(-2, buffer, writer)
+		}},
+		{"uint", AppendUint(prefix, 0xDEADBEEF), func(buffer []byte, writer io.Writer) error {
+			return WriteUint(0xDEADBEEF, buffer, writer)
+		}},
+		{"hyper", AppendHyperInt(prefix, math.MinInt64 + 5), func(buffer []byte, writer io.Writer) error {
+			return WriteHyperInt(math.MinInt64 + 5, buffer, writer)
+		}},
+		{"unsigned hyper", AppendHyperUint(prefix, 1 << 40), func(buffer []byte, writer io.Writer) error {
+			return WriteHyperUint(1 << 40, buffer, writer)
+		}},
+		{"float", AppendFloat(prefix, -1.5), func(buffer []byte, writer io.Writer) error {
+			return WriteFloat(-1.5, buffer, writer)
+		}},
+		{"double", AppendDouble(prefix, math.Pi), func(buffer []byte, writer io.Writer) error {
+			return WriteDouble(math.Pi, buffer, writer)
+		}},
+		{"bool", AppendBool(prefix, true), func(buffer []byte, writer io.Writer) error {
+			return WriteUint(1, buffer, writer)
+		}},
+	}
+	for _, test := range cases {
+		expected := append([]byte{0xEE}, writtenBytes(t, test.write)...)
+		if !bytes.Equal(test.appended, expected) {
+			t.Fatalf("%s: appended %x, want %x", test.name, test.appended, expected)
+		}
+	}
+}
+
+func TestAppendOpaqueAndString(t *testing.T) {
+	if fixed := AppendFixedOpaque(nil, []byte("abcde")); string(fixed) != "abcde\x00\x00\x00" {
+		t.Fatalf("AppendFixedOpaque = %q", fixed)
+	}
+	variable, err := AppendVarOpaque([]byte{1}, []byte("ab"), 4)
+	if err != nil || string(variable) != "\x01\x00\x00\x00\x02ab\x00\x00" {
+		t.Fatalf("AppendVarOpaque = %q, %v", variable, err)
+	}
+	text, err := AppendString(nil, "abcd", 4)
+	if err != nil || string(text) != "\x00\x00\x00\x04abcd" {
+		t.Fatalf("AppendString = %q, %v", text, err)
+	}
+	dst := []byte{1, 2}
+	for name, appendTooLong := range map[string]func() ([]byte, error) {
+		"AppendVarOpaque": func() ([]byte, error) {
+			return AppendVarOpaque(dst, []byte("abcde"), 4)
+		},
+		"AppendString": func() ([]byte, error) {
+			return AppendString(dst, "abcde", 4)
+		},
+	} {
+		extended, err := appendTooLong()
+		if !errors.Is(err, ErrMaxLengthExceeded) || !bytes.Equal(extended, dst) {
+			t.Fatalf("%s = %x, %v, want dst unchanged and a maximum length error", name, extended, err)
+		}
+	}
+}
+
+var generatedPayload = []byte("abc")
+
+func generatePayload(buffer []byte, writer io.Writer) (err error) {
+	_, err = writer.Write(generatedPayload)
+	return
+}
+
+var generatedElements = []Packet {
+	ByteSlicePacket {
+		Bytes: []byte{0, 0, 0, 1},
+	},
+	ByteSlicePacket {
+		Bytes: []byte{0, 0, 0, 2},
+	},
+}
+
+func generateElements(sink PacketSink[int]) error {
+	for _, element := range generatedElements {
+		err := sink(element)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type plainPacket struct {
+	Packet
+}
+
+func appendablePackets() map[string]AppendablePacket {
+	return map[string]AppendablePacket {
+		"byte slice": ByteSlicePacket {
+			Bytes: []byte("xyz"),
+		},
+		"padding": &PaddingPacket {
+			ShortPacket: ByteSlicePacket {
+				Bytes: []byte("ab"),
+			},
+			RequiredLength: 5,
+		},
+		"union": &UnionPacket {
+			Discriminant: 1,
+			Arm: ByteSlicePacket {
+				Bytes: []byte{0, 0, 0, 9},
+			},
+			Cases: AnyCase,
+		},
+		"fixed opaque generator": &FixedLengthOpaqueGeneratorPacket {
+			Generator: generatePayload,
+			ExpectedSize: 3,
+		},
+		"variable opaque generator": &VariableLengthOpaqueGeneratorPacket {
+			Generator: generatePayload,
+			ExpectedSize: 5,
+			MaxSize: 8,
+			Padding: ZeroBytePadder,
+		},
+		"fixed array generator": &FixedLengthArrayGeneratorPacket[int] {
+			Generator: generateElements,
+			ExpectedSize: 2,
+		},
+		"variable array generator": &VariableLengthArrayGeneratorPacket[int] {
+			Generator: generateElements,
+			ExpectedSize: 2,
+			MaxSize: 4,
+		},
+	}
+}
+
+func TestAppendToMatchesWriteTo(t *testing.T) {
+	for name, packet := range appendablePackets() {
+		expected := append([]byte{0xEE}, writtenBytes(t, packet.WriteTo)...)
+		appended, err := packet.AppendTo([]byte{0xEE})
+		if err != nil || !bytes.Equal(appended, expected) {
+			t.Fatalf("%s: AppendTo = %x, %v, want %x", name, appended, err, expected)
+		}
+		appended, err = AppendPacket([]byte{0xEE}, plainPacket{packet})
+		if err != nil || !bytes.Equal(appended, expected) {
+			t.Fatalf("%s: AppendPacket without AppendTo = %x, %v, want %x", name, appended, err, expected)
+		}
+	}
+}
+
+func TestAppendToAllocations(t *testing.T) {
+	dst := make([]byte, 0, 256)
+	scratch := make([]byte, 8)
+	for name, packet := range appendablePackets() {
+		packet.AppendTo(dst)
+		appendAllocations := testing.AllocsPerRun(50, func() {
+			packet.AppendTo(dst)
+		})
+		writeAllocations := testing.AllocsPerRun(50, func() {
+			packet.WriteTo(scratch, io.Discard)
+		})
+		switch packet.(type) {
+			case *FixedLengthArrayGeneratorPacket[int], *VariableLengthArrayGeneratorPacket[int]:
+				if appendAllocations > writeAllocations {
+					t.Fatalf("%s: AppendTo allocates %v times, WriteTo only %v", name, appendAllocations, writeAllocations)
+				}
+			default:
+				if appendAllocations != 0 {
+					t.Fatalf("%s: AppendTo allocates %v times, want none", name, appendAllocations)
+				}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package goxdr
+
+import (
+	"io"
+	"bytes"
+	"errors"
+	"testing"
+	"context"
+)
+
+func checkCancelled(t *testing.T, name string, err error, count uint64) {
+	var cancelError *CancelledError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &cancelError) {
+		t.Fatalf("%s: got %v, want cancellation", name, err)
+	}
+	if cancelError.Count != count {
+		t.Fatalf("%s: cancelled after %d, want %d", name, cancelError.Count, count)
+	}
+}
+
+func cancellingElements(count int, cancel context.CancelFunc) PacketGenerator[int] {
+	return func(sink PacketSink[int]) error {
+		for index := 0; index < count; index++ {
+			if index == 2 {
+				cancel()
+			}
+			err := sink(ByteSlicePacket {
+				Bytes: []byte{0, 0, 0, byte(index)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestArrayWriterCancelledMidArray(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	err := WriteVariableLengthArrayGeneratorContext(ctx, cancellingElements(5, cancel), 5, 5, make([]byte, 8), &out, nil)
+	checkCancelled(t, "variable-length array", err, 2)
+	if out.Len() != 12 {
+		t.Fatalf("wrote %d bytes, want the count and two elements", out.Len())
+	}
+}
+
+func TestArrayWriterCancelledWhilePadding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	padded := 0
+	padding := func(buffer []byte, writer io.Writer) error {
+		padded++
+		cancel()
+		return WriteUint(0, buffer, writer)
+	}
+	err := WriteFixedLengthArrayGeneratorContext(ctx, intElements(1), 4, make([]byte, 8), io.Discard, padding)
+	checkCancelled(t, "array padding", err, 2)
+	if padded != 1 {
+		t.Fatalf("padded %d elements, want 1", padded)
+	}
+}
+
+type cancellingReader struct {
+	reader io.Reader
+	cancel context.CancelFunc
+}
+
+func(reader *cancellingReader) Read(bytes []byte) (int, error) {
+	reader.cancel()
+	return reader.reader.Read(bytes[0:3])
+}
+
+func TestOpaqueReaderCancelledMidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &cancellingReader {
+		reader: bytes.NewReader([]byte("abcdefgh")),
+		cancel: cancel,
+	}
+	var out bytes.Buffer
+	err := WriteVariableLengthOpaqueReaderContext(ctx, reader, 8, 8, make([]byte, 8), &out, nil)
+	checkCancelled(t, "opaque reader", err, 3)
+	if out.String() != "\x00\x00\x00\x08abc" {
+		t.Fatalf("wrote %q, want the length and the first read", out.String())
+	}
+}
+
+func TestOpaqueGeneratorCancelledMidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	generator := func(buffer []byte, writer io.Writer) error {
+		_, err := writer.Write([]byte("ab"))
+		cancel()
+		if err == nil {
+			_, err = writer.Write([]byte("cd"))
+		}
+		return err
+	}
+	var out bytes.Buffer
+	err := WriteFixedLengthOpaqueGeneratorContext(ctx, generator, 4, make([]byte, 8), &out, nil)
+	checkCancelled(t, "opaque generator", err, 2)
+	if out.String() != "ab" {
+		t.Fatalf("wrote %q, want only the bytes before cancellation", out.String())
+	}
+}
+
+func TestWritersCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	err := WriteVariableLengthOpaqueGeneratorContext(ctx, generatePayload, 3, 4, make([]byte, 8), &out, nil)
+	checkCancelled(t, "variable-length opaque generator", err, 0)
+	err = WriteVariableLengthArrayGeneratorContext(ctx, intElements(1), 1, 1, make([]byte, 8), &out, nil)
+	checkCancelled(t, "variable-length array", err, 0)
+	if out.Len() != 0 {
+		t.Fatalf("wrote %d bytes after cancellation", out.Len())
+	}
+}
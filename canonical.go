@@ -0,0 +1,50 @@
+package goxdr
+
+import (
+	"bytes"
+)
+
+type Reencoder func() (Packet, error)
+
+func encodePacket(packet Packet) (encoded []byte, err error) {
+	var buffer bytes.Buffer
+	buffer.Grow(int(packet.ByteSize()))
+	err = packet.WriteTo(make([]byte, minBulkTransferBufferSize), &buffer)
+	if err == nil {
+		encoded = buffer.Bytes()
+	}
+	return
+}
+
+func Canonicalize(data []byte, state ReadState, reencode Reencoder) (canonical []byte, err error) {
+	_, err = decodeBytes(state, data)
+	if err != nil {
+		return
+	}
+	var packet Packet
+	packet, err = reencode()
+	if err == nil {
+		canonical, err = encodePacket(packet)
+	}
+	return
+}
+
+func VerifyCanonical(data []byte, state ReadState, reencode Reencoder) (err error) {
+	var canonical []byte
+	canonical, err = Canonicalize(data, state, reencode)
+	if err != nil {
+		return
+	}
+	offset := 0
+	for offset < len(data) && offset < len(canonical) && data[offset] == canonical[offset] {
+		offset++
+	}
+	if offset < len(data) || offset < len(canonical) {
+		err = &NonCanonicalError {
+			Offset: uint64(offset),
+			CanonicalLength: len(canonical),
+			ActualLength: len(data),
+		}
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package goxdr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func lenientBool() (ReadState, Reencoder) {
+	state, _ := NewPrimitiveReadState(4)
+	return state, func() (Packet, error) {
+		return ByteSlicePacket {
+			Bytes: AppendBool(nil, state.AsUint() != 0),
+		}, nil
+	}
+}
+
+func lenientOpaque() (ReadState, Reencoder) {
+	collector := &byteCollector{}
+	state := &FixedLengthOpaqueReadState {
+		ExpectedLength: 3,
+		Handler: collector,
+		PaddingPolicy: PaddingLenient,
+	}
+	return state, func() (Packet, error) {
+		return &PaddingPacket {
+			ShortPacket: ByteSlicePacket {
+				Bytes: collector.bytes,
+			},
+			RequiredLength: 4,
+		}, nil
+	}
+}
+
+func TestCanonicalizeAcceptsCanonicalInput(t *testing.T) {
+	data := []byte{0, 0, 0, 1}
+	state, reencode := lenientBool()
+	canonical, err := Canonicalize(data, state, reencode)
+	if err != nil || !bytes.Equal(canonical, data) {
+		t.Fatalf("Canonicalize = %x, %v, want %x", canonical, err, data)
+	}
+	state, reencode = lenientBool()
+	if err := VerifyCanonical(data, state, reencode); err != nil {
+		t.Fatalf("VerifyCanonical = %v", err)
+	}
+}
+
+func TestVerifyCanonicalReportsDivergence(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		codec func() (ReadState, Reencoder)
+		offset uint64
+		canonicalLength int
+	} {
+		{"non-canonical bool", []byte{0, 0, 0, 2}, lenientBool, 3, 4},
+		{"non-zero padding", []byte{'a', 'b', 'c', 7}, lenientOpaque, 3, 4},
+		{"trailing data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, lenientBool, 4, 4},
+	}
+	for _, test := range cases {
+		state, reencode := test.codec()
+		err := VerifyCanonical(test.data, state, reencode)
+		var nonCanonical *NonCanonicalError
+		if !errors.Is(err, ErrNonCanonical) || !errors.As(err, &nonCanonical) {
+			t.Fatalf("%s: VerifyCanonical = %v, want non-canonical error", test.name, err)
+		}
+		if nonCanonical.Offset != test.offset || nonCanonical.CanonicalLength != test.canonicalLength ||
+				nonCanonical.ActualLength != len(test.data) {
+			t.Fatalf("%s: got %+v, want divergence at offset %d", test.name, nonCanonical, test.offset)
+		}
+	}
+	state, reencode := lenientOpaque()
+	canonical, err := Canonicalize([]byte{'a', 'b', 'c', 7}, state, reencode)
+	if err != nil || string(canonical) != "abc\x00" {
+		t.Fatalf("Canonicalize = %q, %v, want zero padding", canonical, err)
+	}
+}
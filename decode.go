@@ -0,0 +1,45 @@
+package goxdr
+
+func decodeBytes(state ReadState, data []byte) (consumed int, err error) {
+	var isFull bool
+	for consumed < len(data) && !isFull {
+		var readCount int
+		readCount, isFull = state.Update(data[consumed:])
+		if readCount < 0 || readCount > len(data) - consumed {
+			err = &OverreadError {
+				Subject: "Root read state",
+				ReadCount: readCount,
+				Offered: len(data) - consumed,
+			}
+			return
+		}
+		consumed += readCount
+		if readCount == 0 && !isFull {
+			break
+		}
+	}
+	err = state.EndPacket()
+	return
+}
+
+func NewTrailingDataError(trailing []byte) *TrailingDataError {
+	previewSize := len(trailing)
+	if previewSize > trailingDataPreviewSize {
+		previewSize = trailingDataPreviewSize
+	}
+	preview := make([]byte, previewSize)
+	copy(preview, trailing)
+	return &TrailingDataError {
+		Count: len(trailing),
+		Preview: preview,
+	}
+}
+
+func DecodeExact(state ReadState, data []byte) (err error) {
+	var consumed int
+	consumed, err = decodeBytes(state, data)
+	if err == nil && consumed < len(data) {
+		err = NewTrailingDataError(data[consumed:])
+	}
+	return
+}
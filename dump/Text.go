@@ -0,0 +1,125 @@
+package dump
+
+import (
+	"io"
+	"fmt"
+	"strings"
+	"github.com/UncleSniper/goxdr"
+)
+
+const hexdumpRowSize = 16
+
+type Text struct {
+	Writer io.Writer
+	depth int
+	label string
+	opaque []byte
+	opaqueOffset uint64
+	err error
+}
+
+func NewText(writer io.Writer) *Text {
+	return &Text {
+		Writer: writer,
+	}
+}
+
+func(dumper *Text) Err() error {
+	return dumper.err
+}
+
+func(dumper *Text) takeLabel(name string) string {
+	label := dumper.label
+	dumper.label = ""
+	if len(name) > 0 {
+		if len(label) > 0 {
+			label += " "
+		}
+		label += name
+	}
+	return label
+}
+
+func(dumper *Text) dedent() {
+	dumper.label = ""
+	if dumper.depth > 0 {
+		dumper.depth--
+	}
+}
+
+func(dumper *Text) indentation() string {
+	return strings.Repeat("  ", dumper.depth)
+}
+
+func(dumper *Text) line(offset uint64, label string, text string) {
+	if dumper.err != nil {
+		return
+	}
+	if len(label) > 0 {
+		_, dumper.err = fmt.Fprintf(dumper.Writer, "%08x  %s%s: %s\n", offset, dumper.indentation(), label, text)
+	} else {
+		_, dumper.err = fmt.Fprintf(dumper.Writer, "%08x  %s%s\n", offset, dumper.indentation(), text)
+	}
+}
+
+func(dumper *Text) hexdump() {
+	indentation := dumper.indentation() + "  "
+	for start := 0; start < len(dumper.opaque) && dumper.err == nil; start += hexdumpRowSize {
+		end := start + hexdumpRowSize
+		if end > len(dumper.opaque) {
+			end = len(dumper.opaque)
+		}
+		row := dumper.opaque[start:end]
+		var digits strings.Builder
+		for index, value := range row {
+			if index > 0 {
+				digits.WriteString(" ")
+			}
+			fmt.Fprintf(&digits, "%02x", value)
+		}
+		_, dumper.err = fmt.Fprintf(
+			dumper.Writer,
+			"%08x  %s%-47s  |%s|\n",
+			dumper.opaqueOffset + uint64(start),
+			indentation,
+			digits.String(),
+			printableASCII(row),
+		)
+	}
+}
+
+func(dumper *Text) Observe(event *goxdr.DecodeEvent) {
+	switch event.Kind {
+		case goxdr.EventPrimitive:
+			dumper.line(event.Offset, dumper.takeLabel(event.Name), FormatPrimitive(event.Bytes))
+		case goxdr.EventBeginArray:
+			dumper.line(event.Offset, dumper.takeLabel(event.Name), fmt.Sprintf("array[%d]", event.Length))
+			dumper.depth++
+		case goxdr.EventBeginElement:
+			dumper.label = fmt.Sprintf("[%d]", event.Index)
+		case goxdr.EventEndArray:
+			dumper.dedent()
+		case goxdr.EventBeginOpaque:
+			dumper.line(event.Offset, dumper.takeLabel(event.Name), fmt.Sprintf("opaque[%d]", event.Length))
+			dumper.opaque = dumper.opaque[:0]
+			dumper.opaqueOffset = event.Offset
+		case goxdr.EventOpaqueData:
+			dumper.opaque = append(dumper.opaque, event.Bytes...)
+		case goxdr.EventEndOpaque:
+			dumper.hexdump()
+			dumper.opaque = dumper.opaque[:0]
+		case goxdr.EventBeginUnion:
+			dumper.line(event.Offset, dumper.takeLabel(event.Name), FormatSignedDiscriminant(event.Discriminant, event.Signed, event.Arm))
+			dumper.depth++
+			dumper.label = event.Arm
+		case goxdr.EventEndUnion:
+			dumper.dedent()
+		case goxdr.EventBeginStruct:
+			dumper.line(event.Offset, dumper.takeLabel(event.Name), "struct")
+			dumper.depth++
+		case goxdr.EventEndStruct:
+			dumper.dedent()
+	}
+}
+
+var _ goxdr.DecodeObserver = (&Text{}).Observe
@@ -0,0 +1,81 @@
+package dump
+
+import (
+	"strings"
+	"testing"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+func TestTextEmptyStruct(t *testing.T) {
+	var out strings.Builder
+	context := goxdr.NewDecodeContext(goxdr.DefaultLimits)
+	context.Observer = NewText(&out).Observe
+	state, err := schema.NewDynamicReadState(schema.Struct("empty"), context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = goxdr.DecodeExact(state, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "struct") {
+		t.Fatalf("output %q lacks the struct line", out.String())
+	}
+}
+
+func TestTextClampsUnbalancedEnd(t *testing.T) {
+	var out strings.Builder
+	dumper := NewText(&out)
+	dumper.Observe(&goxdr.DecodeEvent {
+		Kind: goxdr.EventEndStruct,
+	})
+	dumper.Observe(&goxdr.DecodeEvent {
+		Kind: goxdr.EventPrimitive,
+		Bytes: []byte{0, 0, 0, 1},
+	})
+	if dumper.Err() != nil {
+		t.Fatal(dumper.Err())
+	}
+}
+
+func TestTreeClampsUnbalancedEnd(t *testing.T) {
+	tree := NewTree()
+	tree.Observe(&goxdr.DecodeEvent {
+		Kind: goxdr.EventEndUnion,
+	})
+	tree.Observe(&goxdr.DecodeEvent {
+		Kind: goxdr.EventPrimitive,
+		Bytes: []byte{0, 0, 0, 1},
+	})
+	if len(tree.Roots) != 1 {
+		t.Fatalf("Roots = %d, want 1", len(tree.Roots))
+	}
+}
+
+func signedUnionState(observer goxdr.DecodeObserver) *goxdr.TypedUnionReadState[any, int32] {
+	state := goxdr.NewTypedUnionReadState[any, int32]("choice", nil)
+	state.SetArm(-2, goxdr.VoidArm[any])
+	state.DecodeContext = goxdr.NewDecodeContext(goxdr.DefaultLimits)
+	state.DecodeContext.Observer = observer
+	return state
+}
+
+func TestSignedDiscriminants(t *testing.T) {
+	var out strings.Builder
+	err := goxdr.DecodeExact(signedUnionState(NewText(&out).Observe), []byte{0xFF, 0xFF, 0xFF, 0xFE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "union -2 ") || strings.Contains(out.String(), "4294967294") {
+		t.Fatalf("Text output %q does not show a signed discriminant", out.String())
+	}
+	tree := NewTree()
+	err = goxdr.DecodeExact(signedUnionState(tree.Observe), []byte{0xFF, 0xFF, 0xFF, 0xFE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Roots) != 1 || tree.Roots[0].Discriminant == nil || *tree.Roots[0].Discriminant != -2 {
+		t.Fatalf("Tree roots = %v, want discriminant -2", tree.Roots)
+	}
+}
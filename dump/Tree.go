@@ -0,0 +1,140 @@
+package dump
+
+import (
+	"io"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/UncleSniper/goxdr"
+)
+
+type Node struct {
+	Kind string `json:"kind"`
+	Name string `json:"name,omitempty"`
+	Index *uint32 `json:"index,omitempty"`
+	Offset uint64 `json:"offset"`
+	Length *uint32 `json:"length,omitempty"`
+	Signed *int64 `json:"signed,omitempty"`
+	Unsigned *uint64 `json:"unsigned,omitempty"`
+	Hex string `json:"hex,omitempty"`
+	Discriminant *int64 `json:"discriminant,omitempty"`
+	Arm string `json:"arm,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+	data []byte
+}
+
+type Tree struct {
+	Roots []*Node
+	stack []*Node
+	pendingIndex *uint32
+}
+
+func NewTree() *Tree {
+	return &Tree{}
+}
+
+func(tree *Tree) Reset() {
+	tree.Roots = nil
+	tree.stack = nil
+	tree.pendingIndex = nil
+}
+
+func(tree *Tree) top() *Node {
+	if len(tree.stack) == 0 {
+		return nil
+	}
+	return tree.stack[len(tree.stack) - 1]
+}
+
+func(tree *Tree) add(node *Node) {
+	node.Index = tree.pendingIndex
+	tree.pendingIndex = nil
+	if parent := tree.top(); parent != nil {
+		parent.Children = append(parent.Children, node)
+	} else {
+		tree.Roots = append(tree.Roots, node)
+	}
+}
+
+func(tree *Tree) push(node *Node) {
+	tree.add(node)
+	tree.stack = append(tree.stack, node)
+}
+
+func(tree *Tree) pop() {
+	if len(tree.stack) > 0 {
+		tree.stack = tree.stack[:len(tree.stack) - 1]
+	}
+	tree.pendingIndex = nil
+}
+
+func(tree *Tree) Observe(event *goxdr.DecodeEvent) {
+	switch event.Kind {
+		case goxdr.EventPrimitive:
+			signed, unsigned, digits := primitiveValues(event.Bytes)
+			tree.add(&Node {
+				Kind: "primitive",
+				Name: event.Name,
+				Offset: event.Offset,
+				Signed: &signed,
+				Unsigned: &unsigned,
+				Hex: digits,
+			})
+		case goxdr.EventBeginArray:
+			length := event.Length
+			tree.push(&Node {
+				Kind: "array",
+				Name: event.Name,
+				Offset: event.Offset,
+				Length: &length,
+			})
+		case goxdr.EventBeginElement:
+			index := event.Index
+			tree.pendingIndex = &index
+		case goxdr.EventBeginOpaque:
+			length := event.Length
+			tree.push(&Node {
+				Kind: "opaque",
+				Name: event.Name,
+				Offset: event.Offset,
+				Length: &length,
+			})
+		case goxdr.EventOpaqueData:
+			if node := tree.top(); node != nil {
+				node.data = append(node.data, event.Bytes...)
+			}
+		case goxdr.EventEndOpaque:
+			if node := tree.top(); node != nil {
+				node.Hex = hex.EncodeToString(node.data)
+				node.data = nil
+			}
+			tree.pop()
+		case goxdr.EventBeginUnion:
+			discriminant := int64(event.Discriminant)
+			if event.Signed {
+				discriminant = int64(int32(event.Discriminant))
+			}
+			tree.push(&Node {
+				Kind: "union",
+				Name: event.Name,
+				Offset: event.Offset,
+				Discriminant: &discriminant,
+				Arm: event.Arm,
+			})
+		case goxdr.EventBeginStruct:
+			tree.push(&Node {
+				Kind: "struct",
+				Name: event.Name,
+				Offset: event.Offset,
+			})
+		case goxdr.EventEndArray, goxdr.EventEndUnion, goxdr.EventEndStruct:
+			tree.pop()
+	}
+}
+
+func(tree *Tree) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tree.Roots)
+}
+
+var _ goxdr.DecodeObserver = (&Tree{}).Observe
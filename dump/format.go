@@ -0,0 +1,67 @@
+package dump
+
+import (
+	"fmt"
+	"encoding/binary"
+)
+
+func primitiveValues(bytes []byte) (signed int64, unsigned uint64, hex string) {
+	switch len(bytes) {
+		case 4:
+			digits := binary.BigEndian.Uint32(bytes)
+			signed = int64(int32(digits))
+			unsigned = uint64(digits)
+			hex = fmt.Sprintf("0x%08x", digits)
+		case 8:
+			unsigned = binary.BigEndian.Uint64(bytes)
+			signed = int64(unsigned)
+			hex = fmt.Sprintf("0x%016x", unsigned)
+		default:
+			hex = fmt.Sprintf("%x", bytes)
+	}
+	return
+}
+
+func FormatPrimitive(bytes []byte) string {
+	signed, unsigned, hex := primitiveValues(bytes)
+	if signed < 0 {
+		return fmt.Sprintf("%d / %d (%s)", signed, unsigned, hex)
+	}
+	return fmt.Sprintf("%d (%s)", unsigned, hex)
+}
+
+func FormatDiscriminant(discriminant uint32, arm string) string {
+	text := "union " + FormatPrimitive([]byte {
+		byte(discriminant >> 24),
+		byte(discriminant >> 16),
+		byte(discriminant >> 8),
+		byte(discriminant),
+	})
+	if len(arm) > 0 {
+		text += " -> " + arm
+	}
+	return text
+}
+
+func FormatSignedDiscriminant(discriminant uint32, signed bool, arm string) string {
+	if !signed {
+		return FormatDiscriminant(discriminant, arm)
+	}
+	text := fmt.Sprintf("union %d (0x%08x)", int32(discriminant), discriminant)
+	if len(arm) > 0 {
+		text += " -> " + arm
+	}
+	return text
+}
+
+func printableASCII(bytes []byte) string {
+	printable := make([]byte, len(bytes))
+	for index, value := range bytes {
+		if value >= 0x20 && value < 0x7f {
+			printable[index] = value
+		} else {
+			printable[index] = '.'
+		}
+	}
+	return string(printable)
+}
@@ -1,13 +1,28 @@
 package goxdr
 
 import (
+	"errors"
 	"strings"
 	"strconv"
+	"encoding/hex"
 )
 
+var ErrTruncated = errors.New("Data is truncated")
+var ErrMaxLengthExceeded = errors.New("Maximum length exceeded")
+var ErrLengthMismatch = errors.New("Actual length does not match expected length")
+var ErrNonZeroPadding = errors.New("Padding bytes are not zero")
+var ErrBadBool = errors.New("Boolean value is neither 0 nor 1")
+var ErrOverflow = errors.New("Value exceeds the range of uint32")
+var ErrOverread = errors.New("Read state read more bytes than were offered")
+var ErrNilReadState = errors.New("Read state is nil")
+var ErrNonCanonical = errors.New("Encoding is not canonical")
+var ErrTrailingData = errors.New("Root value is followed by trailing data")
+var ErrNoUnionCases = errors.New("Union cases are not declared")
+
 type OpaqueHandlerError struct {
 	PropagatedError error
 	HandlerName string
+	Offset uint64
 }
 
 func(err *OpaqueHandlerError) Error() string {
@@ -18,6 +33,8 @@ func(err *OpaqueHandlerError) Error() string {
 	} else {
 		builder.WriteString("Opaque data handler reported error")
 	}
+	builder.WriteString(" at offset ")
+	builder.WriteString(strconv.FormatUint(err.Offset, 10))
 	if err.PropagatedError != nil {
 		builder.WriteString(": ")
 		builder.WriteString(err.PropagatedError.Error())
@@ -25,9 +42,14 @@ func(err *OpaqueHandlerError) Error() string {
 	return builder.String()
 }
 
+func(err *OpaqueHandlerError) Unwrap() error {
+	return err.PropagatedError
+}
+
 type UnionDiscriminantError struct {
 	Discriminant uint32
 	HandlerName string
+	Signed bool
 }
 
 func(err *UnionDiscriminantError) Error() string {
@@ -38,6 +60,334 @@ func(err *UnionDiscriminantError) Error() string {
 	} else {
 		builder.WriteString("Tagged union reported unrecognized discriminant: ")
 	}
-	builder.WriteString(strconv.FormatUint(uint64(err.Discriminant), 10))
+	if err.Signed {
+		builder.WriteString(strconv.FormatInt(int64(int32(err.Discriminant)), 10))
+	} else {
+		builder.WriteString(strconv.FormatUint(uint64(err.Discriminant), 10))
+	}
+	return builder.String()
+}
+
+type LimitExceededError struct {
+	Limit LimitKind
+	Maximum uint64
+	Actual uint64
+}
+
+func(err *LimitExceededError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Decoding limit on ")
+	builder.WriteString(err.Limit.String())
+	builder.WriteString(" exceeded: maximum is ")
+	builder.WriteString(strconv.FormatUint(err.Maximum, 10))
+	builder.WriteString(", but encountered ")
+	builder.WriteString(strconv.FormatUint(err.Actual, 10))
+	return builder.String()
+}
+
+type DecodeError struct {
+	Offset uint64
+	Path string
+	Cause error
+}
+
+func(err *DecodeError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Decoding failed")
+	if len(err.Path) > 0 {
+		builder.WriteString(" in ")
+		builder.WriteString(err.Path)
+	}
+	builder.WriteString(" at offset ")
+	builder.WriteString(strconv.FormatUint(err.Offset, 10))
+	if err.Cause != nil {
+		builder.WriteString(": ")
+		builder.WriteString(err.Cause.Error())
+	}
+	return builder.String()
+}
+
+func(err *DecodeError) Unwrap() error {
+	return err.Cause
+}
+
+func joinDecodePath(parent string, child string) string {
+	switch {
+		case len(parent) == 0:
+			return child
+		case len(child) == 0:
+			return parent
+		case child[0] == '[':
+			return parent + child
+		default:
+			return parent + "." + child
+	}
+}
+
+func WrapDecodeError(err error, segment string, offset uint64) error {
+	if err == nil {
+		return nil
+	}
+	if decodeError, ok := err.(*DecodeError); ok {
+		return &DecodeError {
+			Offset: offset + decodeError.Offset,
+			Path: joinDecodePath(segment, decodeError.Path),
+			Cause: decodeError.Cause,
+		}
+	}
+	return &DecodeError {
+		Offset: offset,
+		Path: segment,
+		Cause: err,
+	}
+}
+
+type TruncatedError struct {
+	Subject string
+	Size uint64
+	Missing uint64
+}
+
+func(err *TruncatedError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Missing ")
+	builder.WriteString(strconv.FormatUint(err.Missing, 10))
+	builder.WriteString(" bytes for ")
+	builder.WriteString(err.Subject)
+	builder.WriteString(" of size ")
+	builder.WriteString(strconv.FormatUint(err.Size, 10))
+	return builder.String()
+}
+
+func(err *TruncatedError) Unwrap() error {
+	return ErrTruncated
+}
+
+type MaxLengthError struct {
+	Subject string
+	Maximum uint32
+	Actual uint32
+}
+
+func(err *MaxLengthError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(err.Subject)
+	builder.WriteString(" has maximum length ")
+	builder.WriteString(strconv.FormatUint(uint64(err.Maximum), 10))
+	builder.WriteString(", but encountered length ")
+	builder.WriteString(strconv.FormatUint(uint64(err.Actual), 10))
+	return builder.String()
+}
+
+func(err *MaxLengthError) Unwrap() error {
+	return ErrMaxLengthExceeded
+}
+
+type LengthMismatchError struct {
+	Subject string
+	Expected uint64
+	Actual uint64
+}
+
+func(err *LengthMismatchError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Expected ")
+	builder.WriteString(err.Subject)
+	builder.WriteString(" length (")
+	builder.WriteString(strconv.FormatUint(err.Expected, 10))
+	builder.WriteString(") does not match actual ")
+	builder.WriteString(err.Subject)
+	builder.WriteString(" length (")
+	builder.WriteString(strconv.FormatUint(err.Actual, 10))
+	builder.WriteString(")")
+	return builder.String()
+}
+
+func(err *LengthMismatchError) Unwrap() error {
+	return ErrLengthMismatch
+}
+
+type BoolError struct {
+	Value uint32
+}
+
+func(err *BoolError) Error() string {
+	return "Expected boolean value 0 or 1, but encountered " + strconv.FormatUint(uint64(err.Value), 10)
+}
+
+func(err *BoolError) Unwrap() error {
+	return ErrBadBool
+}
+
+type OverflowError struct {
+	Subject string
+	Base uint64
+	Increment uint64
+}
+
+func(err *OverflowError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(err.Subject)
+	builder.WriteString(" of ")
+	builder.WriteString(strconv.FormatUint(err.Base, 10))
+	builder.WriteString(" plus ")
+	builder.WriteString(strconv.FormatUint(err.Increment, 10))
+	builder.WriteString(" exceeds the range of uint32")
 	return builder.String()
 }
+
+func(err *OverflowError) Unwrap() error {
+	return ErrOverflow
+}
+
+type OverreadError struct {
+	Subject string
+	ReadCount int
+	Offered int
+}
+
+func(err *OverreadError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(err.Subject)
+	builder.WriteString(" read ")
+	builder.WriteString(strconv.Itoa(err.ReadCount))
+	builder.WriteString(" bytes, but was supposed to only read ")
+	builder.WriteString(strconv.Itoa(err.Offered))
+	return builder.String()
+}
+
+func(err *OverreadError) Unwrap() error {
+	return ErrOverread
+}
+
+type NilReadStateError struct {
+	Index uint32
+	Length uint32
+}
+
+func(err *NilReadStateError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Read state factory returned nil for index ")
+	builder.WriteString(strconv.FormatUint(uint64(err.Index), 10))
+	builder.WriteString(" of ")
+	builder.WriteString(strconv.FormatUint(uint64(err.Length), 10))
+	return builder.String()
+}
+
+func(err *NilReadStateError) Unwrap() error {
+	return ErrNilReadState
+}
+
+type NilArmFactoryError struct {
+	Discriminant uint32
+	Signed bool
+}
+
+func(err *NilArmFactoryError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Arm factory for discriminant ")
+	if err.Signed {
+		builder.WriteString(strconv.FormatInt(int64(int32(err.Discriminant)), 10))
+	} else {
+		builder.WriteString(strconv.FormatUint(uint64(err.Discriminant), 10))
+	}
+	builder.WriteString(" is nil")
+	return builder.String()
+}
+
+func(err *NilArmFactoryError) Unwrap() error {
+	return ErrNilReadState
+}
+
+type NonZeroPaddingError struct {
+	Offset uint64
+	Value byte
+}
+
+func(err *NonZeroPaddingError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Padding byte at offset ")
+	builder.WriteString(strconv.FormatUint(err.Offset, 10))
+	builder.WriteString(" is ")
+	builder.WriteString(strconv.FormatUint(uint64(err.Value), 10))
+	builder.WriteString(", but must be zero")
+	return builder.String()
+}
+
+func(err *NonZeroPaddingError) Unwrap() error {
+	return ErrNonZeroPadding
+}
+
+type NonCanonicalError struct {
+	Offset uint64
+	CanonicalLength int
+	ActualLength int
+}
+
+func(err *NonCanonicalError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Encoding is not canonical: first divergence at offset ")
+	builder.WriteString(strconv.FormatUint(err.Offset, 10))
+	builder.WriteString(" (canonical length ")
+	builder.WriteString(strconv.Itoa(err.CanonicalLength))
+	builder.WriteString(", actual length ")
+	builder.WriteString(strconv.Itoa(err.ActualLength))
+	builder.WriteString(")")
+	return builder.String()
+}
+
+func(err *NonCanonicalError) Unwrap() error {
+	return ErrNonCanonical
+}
+
+type CancelledError struct {
+	Subject string
+	Count uint64
+	Cause error
+}
+
+func(err *CancelledError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Write cancelled after ")
+	builder.WriteString(strconv.FormatUint(err.Count, 10))
+	builder.WriteString(" ")
+	builder.WriteString(err.Subject)
+	if err.Cause != nil {
+		builder.WriteString(": ")
+		builder.WriteString(err.Cause.Error())
+	}
+	return builder.String()
+}
+
+func(err *CancelledError) Unwrap() error {
+	return err.Cause
+}
+
+type TrailingDataError struct {
+	Count int
+	Preview []byte
+}
+
+func(err *TrailingDataError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Root value is followed by ")
+	builder.WriteString(strconv.Itoa(err.Count))
+	builder.WriteString(" unconsumed bytes")
+	if len(err.Preview) > 0 {
+		builder.WriteString(": ")
+		for index, value := range err.Preview {
+			if index > 0 {
+				builder.WriteString(" ")
+			}
+			builder.WriteString(hex.EncodeToString([]byte{value}))
+		}
+		if len(err.Preview) < err.Count {
+			builder.WriteString(" ...")
+		}
+	}
+	return builder.String()
+}
+
+func(err *TrailingDataError) Unwrap() error {
+	return ErrTrailingData
+}
@@ -0,0 +1,51 @@
+package goxdr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeErrorKeepsLeafReachable(t *testing.T) {
+	primitiveState, _ := NewPrimitiveReadState(4)
+	state := &TaggedUnionReadState[uint32] {
+		PrimitiveState: primitiveState,
+		HandlerName: "choice",
+	}
+	state.Update([]byte{0, 0, 0, 7})
+	err := state.EndPacket()
+	var decodeError *DecodeError
+	if !errors.As(err, &decodeError) || decodeError.Path != "choice" {
+		t.Fatalf("EndPacket = %v, want DecodeError in choice", err)
+	}
+	var discriminantError *UnionDiscriminantError
+	if !errors.As(err, &discriminantError) || discriminantError.Discriminant != 7 {
+		t.Fatalf("EndPacket = %v, want UnionDiscriminantError for 7", err)
+	}
+}
+
+type failingReadState struct {}
+
+func(state failingReadState) Update(bytes []byte) (int, bool) {
+	return len(bytes), false
+}
+
+func(state failingReadState) EndPacket() error {
+	return errors.New("rejected")
+}
+
+func TestOpaqueHandlerErrorNamesHandlerOnce(t *testing.T) {
+	state := &FixedLengthOpaqueReadState {
+		ExpectedLength: 4,
+		Handler: failingReadState{},
+		HandlerName: "payload",
+	}
+	state.Update([]byte{1, 2, 3, 4})
+	err := state.EndPacket()
+	if err == nil {
+		t.Fatal("EndPacket succeeded, want handler error")
+	}
+	if count := strings.Count(err.Error(), "payload"); count != 1 {
+		t.Fatalf("error %q names the handler %d times, want once", err, count)
+	}
+}
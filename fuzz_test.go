@@ -0,0 +1,208 @@
+package goxdr_test
+
+import (
+	"testing"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/xdrtest"
+)
+
+func checkChunkings(
+	t *testing.T,
+	data []byte,
+	split uint8,
+	newState func() goxdr.ReadState,
+	value func(goxdr.ReadState) any,
+) {
+	err := xdrtest.CheckChunkingInvariants(data, split, newState, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func primitiveValue(state goxdr.ReadState) any {
+	return state.(*goxdr.PrimitiveReadState).AsHyperUint()
+}
+
+func FuzzPrimitiveReadState(f *testing.F) {
+	f.Add([]byte{0x00, 0x00, 0x00, 0x01}, false, uint8(1))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00}, true, uint8(3))
+	f.Add([]byte{0x12}, false, uint8(0))
+	f.Fuzz(func(t *testing.T, data []byte, wide bool, split uint8) {
+		size := 4
+		if wide {
+			size = 8
+		}
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			state, _ := goxdr.NewPrimitiveReadState(size)
+			return state
+		}, primitiveValue)
+	})
+}
+
+type collector struct {
+	bytes []byte
+}
+
+func(handler *collector) Update(bytes []byte) (int, bool) {
+	handler.bytes = append(handler.bytes, bytes...)
+	return len(bytes), false
+}
+
+func(handler *collector) EndPacket() error {
+	return nil
+}
+
+func collectedBytes(handler *collector) any {
+	if handler.bytes == nil {
+		return []byte{}
+	}
+	return handler.bytes
+}
+
+func FuzzFixedLengthOpaqueReadState(f *testing.F) {
+	f.Add([]byte("(quit)\x00\x00"), uint16(6), false, uint8(2))
+	f.Add([]byte("lisp"), uint16(4), true, uint8(1))
+	f.Add([]byte("ab\x00\x01"), uint16(2), true, uint8(0))
+	f.Fuzz(func(t *testing.T, data []byte, length uint16, strict bool, split uint8) {
+		policy := goxdr.PaddingLenient
+		if strict {
+			policy = goxdr.PaddingStrict
+		}
+		handlers := make(map[goxdr.ReadState]*collector)
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			handler := &collector{}
+			state := &goxdr.FixedLengthOpaqueReadState {
+				ExpectedLength: uint32(length),
+				Handler: handler,
+				PaddingPolicy: policy,
+			}
+			handlers[state] = handler
+			return state
+		}, func(state goxdr.ReadState) any {
+			return collectedBytes(handlers[state])
+		})
+	})
+}
+
+func FuzzVariableLengthOpaqueReadState(f *testing.F) {
+	f.Add([]byte("\x00\x00\x00\x09sillyprog\x00\x00\x00"), uint16(255), uint8(5))
+	f.Add([]byte("\x00\x00\x00\x00"), uint16(0), uint8(0))
+	f.Add([]byte("\xff\xff\xff\xff"), uint16(16), uint8(1))
+	f.Fuzz(func(t *testing.T, data []byte, maxLength uint16, split uint8) {
+		handlers := make(map[goxdr.ReadState]*collector)
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			handler := &collector{}
+			primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+			state := &goxdr.VariableLengthOpaqueReadState {
+				PrimitiveState: primitiveState,
+				FixedLengthState: &goxdr.FixedLengthOpaqueReadState {
+					Handler: handler,
+					HandlerName: "data",
+				},
+				MaxLength: uint32(maxLength),
+			}
+			handlers[state] = handler
+			return state
+		}, func(state goxdr.ReadState) any {
+			return collectedBytes(handlers[state])
+		})
+	})
+}
+
+type elementList struct {
+	elements []*goxdr.PrimitiveReadState
+}
+
+func(list *elementList) factory(index uint32, size uint32) (goxdr.TypedReadState[uint32], error) {
+	element, err := goxdr.NewPrimitiveReadState(4)
+	list.elements = append(list.elements, element)
+	return element, err
+}
+
+func(list *elementList) values() any {
+	values := make([]any, len(list.elements))
+	for index, element := range list.elements {
+		values[index] = element.AsUint()
+	}
+	return values
+}
+
+func FuzzFixedLengthArrayReadState(f *testing.F) {
+	f.Add([]byte("\x00\x00\x00\x01\x00\x00\x00\x02"), uint8(2), uint8(3))
+	f.Add([]byte{}, uint8(0), uint8(0))
+	f.Fuzz(func(t *testing.T, data []byte, length uint8, split uint8) {
+		lists := make(map[goxdr.ReadState]*elementList)
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			list := &elementList{}
+			state := &goxdr.FixedLengthArrayReadState[uint32] {
+				ExpectedLength: uint32(length),
+				HandlerFactory: list.factory,
+				HandlerName: "elements",
+			}
+			lists[state] = list
+			return state
+		}, func(state goxdr.ReadState) any {
+			return lists[state].values()
+		})
+	})
+}
+
+func FuzzVariableLengthArrayReadState(f *testing.F) {
+	f.Add([]byte("\x00\x00\x00\x02\x00\x00\x00\x01\x00\x00\x00\x02"), uint8(4), uint8(3))
+	f.Add([]byte("\x7f\xff\xff\xff"), uint8(255), uint8(1))
+	f.Fuzz(func(t *testing.T, data []byte, maxLength uint8, split uint8) {
+		lists := make(map[goxdr.ReadState]*elementList)
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			list := &elementList{}
+			primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+			state := &goxdr.VariableLengthArrayReadState[uint32] {
+				PrimitiveState: primitiveState,
+				FixedLengthState: &goxdr.FixedLengthArrayReadState[uint32] {
+					HandlerFactory: list.factory,
+					HandlerName: "elements",
+				},
+				MaxLength: uint32(maxLength),
+			}
+			lists[state] = list
+			return state
+		}, func(state goxdr.ReadState) any {
+			return lists[state].values()
+		})
+	})
+}
+
+func FuzzTaggedUnionReadState(f *testing.F) {
+	f.Add([]byte("\x00\x00\x00\x01\x00\x00\x00\x07"), uint8(1))
+	f.Add([]byte("\x00\x00\x00\x00"), uint8(2))
+	f.Add([]byte("\xff\xff\xff\xff\x00\x00\x00\x00\x00\x00\x00\x01"), uint8(5))
+	f.Fuzz(func(t *testing.T, data []byte, split uint8) {
+		arms := make(map[goxdr.ReadState]*goxdr.PrimitiveReadState)
+		checkChunkings(t, data, split, func() goxdr.ReadState {
+			primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+			state := &goxdr.TaggedUnionReadState[uint64] {
+				PrimitiveState: primitiveState,
+				HandlerName: "result",
+				SignedDiscriminant: true,
+			}
+			state.Arms = map[uint32]goxdr.TypedReadStateFactory[uint64] {
+				0: goxdr.VoidArm[uint64],
+				1: func(uint32, uint32) (goxdr.TypedReadState[uint64], error) {
+					arm, err := goxdr.NewPrimitiveReadState(4)
+					arms[state] = arm
+					return arm, err
+				},
+				0xffffffff: func(uint32, uint32) (goxdr.TypedReadState[uint64], error) {
+					arm, err := goxdr.NewPrimitiveReadState(8)
+					arms[state] = arm
+					return arm, err
+				},
+			}
+			return state
+		}, func(state goxdr.ReadState) any {
+			if arm := arms[state]; arm != nil {
+				return arm.AsHyperUint()
+			}
+			return nil
+		})
+	})
+}
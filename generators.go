@@ -42,3 +42,5 @@ func TypedPacketSliceGenerator[T any](elements []TypedPacket[T]) PacketGenerator
 		return
 	}
 }
+
+type ElementWriter[T any] func(T, []byte, io.Writer) error
@@ -2,33 +2,48 @@ package goxdr
 
 import (
 	"io"
-	"fmt"
 	"math"
-	"errors"
+	"context"
 )
 
 type countingWriter struct {
 	writer io.Writer
 	count uint32
+	ctx context.Context
+	expected uint32
+	progress ProgressFunc
 }
 
 func(counter *countingWriter) Write(bytes []byte) (writeCount int, err error) {
+	if counter.ctx != nil {
+		err = cancelled(counter.ctx, "bytes", counter.count)
+		if err != nil {
+			return
+		}
+	}
 	writeCount, err = counter.writer.Write(bytes)
 	if err == nil {
 		if int64(writeCount) > int64(math.MaxUint32) {
-			err = errors.New(fmt.Sprintf("Wrote chunk of size %d, which exceeds the range of uint32", writeCount))
+			err = &OverflowError {
+				Subject: "Total write size",
+				Base: uint64(counter.count),
+				Increment: uint64(writeCount),
+			}
 			return
 		}
 		nextCount := counter.count + uint32(writeCount)
 		if nextCount < counter.count {
-			err = errors.New(fmt.Sprintf(
-				"Chunk (of size %d) would cause total write size (%d before chunk) to exceed the range of uint32",
-				writeCount,
-				counter.count,
-			))
+			err = &OverflowError {
+				Subject: "Total write size",
+				Base: uint64(counter.count),
+				Increment: uint64(writeCount),
+			}
 			return
 		}
 		counter.count = nextCount
+		if counter.progress != nil && writeCount > 0 {
+			counter.progress(uint64(nextCount), uint64(counter.expected))
+		}
 	}
 	return
 }
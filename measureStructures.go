@@ -0,0 +1,98 @@
+package goxdr
+
+import (
+	"io"
+	"math"
+)
+
+func paddedSize(size uint32) (padded uint32, err error) {
+	padded = size
+	remainder := size % uint32(4)
+	if remainder > 0 {
+		padded += uint32(4) - remainder
+		if padded < size {
+			err = &OverflowError {
+				Subject: "Padded size",
+				Base: uint64(size),
+				Increment: uint64(uint32(4) - remainder),
+			}
+		}
+	}
+	return
+}
+
+func prefixedSize(size uint32) (prefixed uint32, err error) {
+	if size > math.MaxUint32 - 4 {
+		err = &OverflowError {
+			Subject: "Prefixed size",
+			Base: uint64(size),
+			Increment: 4,
+		}
+	} else {
+		prefixed = size + 4
+	}
+	return
+}
+
+func MeasureFixedLengthOpaqueGenerator(expectedSize uint32) (uint32, error) {
+	return paddedSize(expectedSize)
+}
+
+func MeasureVariableLengthOpaqueGenerator(expectedSize uint32, maxSize uint32) (size uint32, err error) {
+	if expectedSize > maxSize {
+		err = &MaxLengthError {
+			Subject: "Packet",
+			Maximum: maxSize,
+			Actual: expectedSize,
+		}
+		return
+	}
+	size, err = paddedSize(expectedSize)
+	if err == nil {
+		size, err = prefixedSize(size)
+	}
+	return
+}
+
+func MeasureFixedLengthArrayGenerator[T any](
+	generator PacketGenerator[T],
+	expectedSize uint32,
+	buffer []byte,
+	padding ElementPadder[T],
+) (size uint32, err error) {
+	var counter countingWriter
+	counter.writer = io.Discard
+	err = WriteFixedLengthArrayGenerator(generator, expectedSize, buffer, &counter, padding)
+	size = counter.count
+	return
+}
+
+func MeasureVariableLengthArrayGenerator[T any](
+	generator PacketGenerator[T],
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	padding ElementPadder[T],
+) (size uint32, err error) {
+	var counter countingWriter
+	counter.writer = io.Discard
+	err = WriteVariableLengthArrayGenerator(generator, expectedSize, maxSize, buffer, &counter, padding)
+	size = counter.count
+	return
+}
+
+func MeasureArray[T any](elements []T, expectedSize uint32, encode ElementWriter[T], buffer []byte) (size uint32, err error) {
+	var counter countingWriter
+	counter.writer = io.Discard
+	err = WriteArray(elements, expectedSize, encode, buffer, &counter)
+	size = counter.count
+	return
+}
+
+func MeasureVarArray[T any](elements []T, maxSize uint32, encode ElementWriter[T], buffer []byte) (size uint32, err error) {
+	var counter countingWriter
+	counter.writer = io.Discard
+	err = WriteVarArray(elements, maxSize, encode, buffer, &counter)
+	size = counter.count
+	return
+}
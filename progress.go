@@ -0,0 +1,25 @@
+package goxdr
+
+type ProgressFunc func(uint64, uint64)
+
+type Progress struct {
+	Transferred uint64
+	Expected uint64
+}
+
+func(progress Progress) Done() bool {
+	return progress.Transferred >= progress.Expected
+}
+
+func ProgressChannel(channel chan<- Progress) ProgressFunc {
+	return func(transferred uint64, expected uint64) {
+		report := Progress {
+			Transferred: transferred,
+			Expected: expected,
+		}
+		select {
+			case channel <- report:
+			default:
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package goxdr
+
+import (
+	"io"
+	"bytes"
+	"strings"
+	"testing"
+	"context"
+)
+
+type progressLog struct {
+	reports []Progress
+}
+
+func(log *progressLog) report(transferred uint64, expected uint64) {
+	log.reports = append(log.reports, Progress {
+		Transferred: transferred,
+		Expected: expected,
+	})
+}
+
+func(log *progressLog) last(t *testing.T) Progress {
+	if len(log.reports) == 0 {
+		t.Fatal("no progress reported")
+	}
+	return log.reports[len(log.reports) - 1]
+}
+
+func TestProgressChannelNeverBlocks(t *testing.T) {
+	channel := make(chan Progress, 1)
+	progress := ProgressChannel(channel)
+	progress(1, 2)
+	progress(2, 2)
+	if report := <-channel; report.Transferred != 1 {
+		t.Fatalf("got %+v, want the first report", report)
+	}
+}
+
+func TestEmptyOpaqueReportsProgress(t *testing.T) {
+	var readerLog, generatorLog, stateLog progressLog
+	var buffer bytes.Buffer
+	err := WriteFixedLengthOpaqueReaderProgress(
+		context.Background(),
+		strings.NewReader(""),
+		0,
+		make([]byte, 8),
+		&buffer,
+		nil,
+		readerLog.report,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteFixedLengthOpaqueGeneratorProgress(
+		context.Background(),
+		func([]byte, io.Writer) error {
+			return nil
+		},
+		0,
+		make([]byte, 8),
+		&buffer,
+		nil,
+		generatorLog.report,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &FixedLengthOpaqueReadState {
+		Handler: &EmptyReadState{},
+		Progress: stateLog.report,
+	}
+	err = state.EndPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range []*progressLog{&readerLog, &generatorLog, &stateLog} {
+		if report := log.last(t); !report.Done() || report.Expected != 0 {
+			t.Fatalf("got %+v, want a finished zero-length report", report)
+		}
+	}
+}
+
+func TestGeneratorReportsProgress(t *testing.T) {
+	var log progressLog
+	var buffer bytes.Buffer
+	err := WriteVariableLengthOpaqueGeneratorProgress(
+		context.Background(),
+		func(_ []byte, writer io.Writer) (err error) {
+			_, err = writer.Write([]byte("abc"))
+			if err == nil {
+				_, err = writer.Write([]byte("de"))
+			}
+			return
+		},
+		7,
+		16,
+		make([]byte, 8),
+		&buffer,
+		ZeroBytePadder,
+		log.report,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint64{3, 5, 7}
+	if len(log.reports) != len(expected) {
+		t.Fatalf("got %+v, want transfers %v", log.reports, expected)
+	}
+	for index, report := range log.reports {
+		if report.Transferred != expected[index] || report.Expected != 7 {
+			t.Fatalf("report %d is %+v, want %d of 7", index, report, expected[index])
+		}
+	}
+	if buffer.Len() != 12 {
+		t.Fatalf("wrote %d bytes, want 12", buffer.Len())
+	}
+}
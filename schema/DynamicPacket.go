@@ -0,0 +1,359 @@
+package schema
+
+import (
+	"io"
+	"math"
+	"github.com/UncleSniper/goxdr"
+)
+
+type encodedValue struct {
+	t *Type
+	bits uint64
+	bytes []byte
+	children []*encodedValue
+}
+
+func addSize(size *uint64, increment uint64) error {
+	if *size + increment > math.MaxUint32 {
+		return &goxdr.OverflowError {
+			Subject: "Dynamic packet size",
+			Base: *size,
+			Increment: increment,
+		}
+	}
+	*size += increment
+	return nil
+}
+
+func paddedLength(length int) uint64 {
+	return (uint64(length) + uint64(3)) &^ uint64(3)
+}
+
+func subjectAt(subject string, path string) string {
+	if len(path) == 0 {
+		return subject
+	}
+	return subject + " " + path
+}
+
+func clampLength(length int) uint32 {
+	if uint64(length) > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(length)
+}
+
+func encodeValue(t *Type, value any, path string, size *uint64) (encoded *encodedValue, err error) {
+	resolved := t.Resolve()
+	if resolved == nil {
+		err = &UndefinedTypeError {
+			Name: t.Name,
+		}
+		return
+	}
+	if resolved.Kind != KindOptional {
+		value = dereference(value)
+	}
+	encoded = &encodedValue {
+		t: resolved,
+	}
+	ok := true
+	switch resolved.Kind {
+		case KindVoid:
+			ok = value == nil
+		case KindInt:
+			var digits int64
+			digits, ok = coerceInt64(value)
+			ok = ok && digits >= math.MinInt32 && digits <= math.MaxInt32
+			encoded.bits = uint64(uint32(int32(digits)))
+			err = addSize(size, 4)
+		case KindUnsignedInt:
+			encoded.bits, ok = coerceUint64(value)
+			ok = ok && encoded.bits <= math.MaxUint32
+			err = addSize(size, 4)
+		case KindHyper:
+			var digits int64
+			digits, ok = coerceInt64(value)
+			encoded.bits = uint64(digits)
+			err = addSize(size, 8)
+		case KindUnsignedHyper:
+			encoded.bits, ok = coerceUint64(value)
+			err = addSize(size, 8)
+		case KindFloat:
+			if single, isSingle := value.(float32); isSingle {
+				encoded.bits = uint64(math.Float32bits(single))
+				err = addSize(size, 4)
+				break
+			}
+			var number float64
+			number, ok = coerceFloat64(value)
+			encoded.bits = uint64(math.Float32bits(float32(number)))
+			err = addSize(size, 4)
+		case KindDouble:
+			var number float64
+			number, ok = coerceFloat64(value)
+			encoded.bits = math.Float64bits(number)
+			err = addSize(size, 8)
+		case KindBool:
+			var truth bool
+			truth, ok = coerceBool(value)
+			if truth {
+				encoded.bits = 1
+			}
+			err = addSize(size, 4)
+		case KindEnum:
+			var member int32
+			member, ok = coerceEnum(resolved, value)
+			encoded.bits = uint64(uint32(member))
+			err = addSize(size, 4)
+		case KindFixedOpaque, KindVariableOpaque, KindString:
+			err = encodeOpaque(encoded, value, path, size)
+		case KindFixedArray, KindVariableArray:
+			err = encodeArray(encoded, value, path, size)
+		case KindOptional:
+			err = addSize(size, 4)
+			if err == nil && !isAbsent(value) {
+				encoded.bits = 1
+				var element *encodedValue
+				element, err = encodeValue(resolved.Element, dereference(value), path, size)
+				encoded.children = []*encodedValue{element}
+			}
+		case KindStruct:
+			err = encodeStruct(encoded, value, path, size)
+		case KindUnion:
+			err = encodeUnion(encoded, value, path, size)
+		default:
+			ok = false
+	}
+	if err == nil && !ok {
+		err = valueError(path, resolved, value, "")
+	}
+	return
+}
+
+func encodeOpaque(encoded *encodedValue, value any, path string, size *uint64) (err error) {
+	t := encoded.t
+	var ok bool
+	encoded.bytes, ok = coerceBytes(value)
+	if !ok {
+		return valueError(path, t, value, "")
+	}
+	length := len(encoded.bytes)
+	switch {
+		case t.Kind == KindFixedOpaque && uint64(length) != uint64(t.Length):
+			err = &goxdr.LengthMismatchError {
+				Subject: subjectAt("fixed-length opaque", path),
+				Expected: uint64(t.Length),
+				Actual: uint64(length),
+			}
+		case t.Kind != KindFixedOpaque && uint64(length) > uint64(t.Length):
+			err = &goxdr.MaxLengthError {
+				Subject: subjectAt("Variable-length opaque data", path),
+				Maximum: t.Length,
+				Actual: clampLength(length),
+			}
+		case t.Kind == KindFixedOpaque:
+			err = addSize(size, paddedLength(length))
+		default:
+			err = addSize(size, uint64(4) + paddedLength(length))
+	}
+	return
+}
+
+func encodeArray(encoded *encodedValue, value any, path string, size *uint64) (err error) {
+	t := encoded.t
+	elements, ok := coerceSequence(value)
+	if !ok {
+		return valueError(path, t, value, "")
+	}
+	count := len(elements)
+	switch {
+		case t.Kind == KindFixedArray && uint64(count) != uint64(t.Length):
+			return &goxdr.LengthMismatchError {
+				Subject: subjectAt("fixed-length array", path),
+				Expected: uint64(t.Length),
+				Actual: uint64(count),
+			}
+		case t.Kind == KindVariableArray && uint64(count) > uint64(t.Length):
+			return &goxdr.MaxLengthError {
+				Subject: subjectAt("Variable-length array", path),
+				Maximum: t.Length,
+				Actual: clampLength(count),
+			}
+		case t.Kind == KindVariableArray:
+			err = addSize(size, 4)
+			if err != nil {
+				return
+			}
+	}
+	encoded.children = make([]*encodedValue, count)
+	for index, element := range elements {
+		encoded.children[index], err = encodeValue(t.Element, element, indexPath(path, index), size)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func encodeStruct(encoded *encodedValue, value any, path string, size *uint64) (err error) {
+	t := encoded.t
+	fields, ok := coerceMapping(value)
+	if !ok {
+		return valueError(path, t, value, "")
+	}
+	encoded.children = make([]*encodedValue, len(t.Fields))
+	for index, field := range t.Fields {
+		fieldValue, present := fields[field.Name]
+		fieldPath := joinValuePath(path, field.Name)
+		if !present {
+			fieldType := field.Type.Resolve()
+			if fieldType == nil || (fieldType.Kind != KindVoid && fieldType.Kind != KindOptional) {
+				return valueError(fieldPath, field.Type, nil, "missing struct field")
+			}
+		}
+		encoded.children[index], err = encodeValue(field.Type, fieldValue, fieldPath, size)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func encodeUnion(encoded *encodedValue, value any, path string, size *uint64) (err error) {
+	t := encoded.t
+	_, err = t.DiscriminantType()
+	if err != nil {
+		return
+	}
+	union, ok := coerceMapping(value)
+	if !ok {
+		return valueError(path, t, value, "")
+	}
+	tag, present := union[UnionTagKey]
+	if !present {
+		return valueError(path, t, value, "missing union tag")
+	}
+	discriminant, err := encodeValue(t.Discriminant.Type, tag, joinValuePath(path, UnionTagKey), size)
+	if err != nil {
+		return
+	}
+	encoded.bits = discriminant.bits
+	arm := t.Arm(int32(uint32(discriminant.bits)))
+	if arm == nil {
+		return &goxdr.UnionDiscriminantError {
+			Discriminant: uint32(discriminant.bits),
+			HandlerName: joinValuePath(path, UnionTagKey),
+			Signed: t.SignedDiscriminant(),
+		}
+	}
+	armPath := path
+	if len(arm.Name) > 0 {
+		armPath = joinValuePath(path, arm.Name)
+	}
+	armValue, err := encodeValue(arm.Type, union[UnionValueKey], armPath, size)
+	if err == nil {
+		encoded.children = []*encodedValue{armValue}
+	}
+	return
+}
+
+func(encoded *encodedValue) writeTo(buffer []byte, writer io.Writer) (err error) {
+	switch encoded.t.Kind {
+		case KindInt, KindUnsignedInt, KindFloat, KindBool, KindEnum:
+			err = goxdr.WriteUint(uint32(encoded.bits), buffer, writer)
+		case KindHyper, KindUnsignedHyper, KindDouble:
+			err = goxdr.WriteHyperUint(encoded.bits, buffer, writer)
+		case KindFixedOpaque:
+			err = goxdr.WriteFixedLengthOpaquePacket(goxdr.ByteSlicePacket {
+				Bytes: encoded.bytes,
+			}, buffer, writer)
+		case KindVariableOpaque, KindString:
+			err = goxdr.WriteVariableLengthOpaquePacket(goxdr.ByteSlicePacket {
+				Bytes: encoded.bytes,
+			}, encoded.t.Length, buffer, writer)
+		case KindVariableArray, KindOptional, KindUnion:
+			if encoded.t.Kind == KindVariableArray {
+				err = goxdr.WriteUint(uint32(len(encoded.children)), buffer, writer)
+			} else {
+				err = goxdr.WriteUint(uint32(encoded.bits), buffer, writer)
+			}
+			if err != nil {
+				return
+			}
+			fallthrough
+		case KindFixedArray, KindStruct:
+			for _, child := range encoded.children {
+				err = child.writeTo(buffer, writer)
+				if err != nil {
+					return
+				}
+			}
+	}
+	return
+}
+
+func(encoded *encodedValue) appendTo(dst []byte) []byte {
+	switch encoded.t.Kind {
+		case KindInt, KindUnsignedInt, KindFloat, KindBool, KindEnum:
+			dst = goxdr.AppendUint(dst, uint32(encoded.bits))
+		case KindHyper, KindUnsignedHyper, KindDouble:
+			dst = goxdr.AppendHyperUint(dst, encoded.bits)
+		case KindFixedOpaque:
+			dst = goxdr.AppendFixedOpaque(dst, encoded.bytes)
+		case KindVariableOpaque, KindString:
+			dst = goxdr.AppendUint(dst, uint32(len(encoded.bytes)))
+			dst = goxdr.AppendFixedOpaque(dst, encoded.bytes)
+		case KindVariableArray, KindOptional, KindUnion:
+			if encoded.t.Kind == KindVariableArray {
+				dst = goxdr.AppendUint(dst, uint32(len(encoded.children)))
+			} else {
+				dst = goxdr.AppendUint(dst, uint32(encoded.bits))
+			}
+			fallthrough
+		case KindFixedArray, KindStruct:
+			for _, child := range encoded.children {
+				dst = child.appendTo(dst)
+			}
+	}
+	return dst
+}
+
+type DynamicPacket struct {
+	Type *Type
+	Value any
+	encoded *encodedValue
+	size uint32
+}
+
+func NewDynamicPacket(t *Type, value any) (packet *DynamicPacket, err error) {
+	var size uint64
+	encoded, err := encodeValue(t, value, "", &size)
+	if err != nil {
+		return
+	}
+	packet = &DynamicPacket {
+		Type: t,
+		Value: value,
+		encoded: encoded,
+		size: uint32(size),
+	}
+	return
+}
+
+func(packet *DynamicPacket) ByteSize() uint32 {
+	return packet.size
+}
+
+func(packet *DynamicPacket) WriteTo(buffer []byte, writer io.Writer) error {
+	if len(buffer) < 8 {
+		buffer = make([]byte, 8)
+	}
+	return packet.encoded.writeTo(buffer, writer)
+}
+
+func(packet *DynamicPacket) AppendTo(dst []byte) ([]byte, error) {
+	return packet.encoded.appendTo(dst), nil
+}
+
+var _ goxdr.AppendablePacket = &DynamicPacket{}
@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+	"github.com/UncleSniper/goxdr"
+)
+
+func TestUnionWithoutDiscriminantType(t *testing.T) {
+	for _, discriminant := range []*Field{nil, NewField("tag", Reference("missing"))} {
+		union := Union("broken", discriminant, nil, NewArm("value", Int(), 0))
+		var undefined *UndefinedTypeError
+		_, err := NewDynamicPacket(union, map[string]any {
+			UnionTagKey: 0,
+			UnionValueKey: 1,
+		})
+		if !errors.As(err, &undefined) {
+			t.Fatalf("NewDynamicPacket = %v, want UndefinedTypeError", err)
+		}
+		state, err := NewDynamicReadState(union, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = goxdr.DecodeExact(state, []byte{0, 0, 0, 0, 0, 0, 0, 1})
+		if !errors.As(err, &undefined) {
+			t.Fatalf("DecodeExact = %v, want UndefinedTypeError", err)
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package schema
+
+import (
+	"github.com/UncleSniper/goxdr"
+)
+
+const UnionTagKey = "tag"
+const UnionValueKey = "value"
+
+type dynamicNode interface {
+	goxdr.ReadState
+	value() any
+}
+
+type voidNode struct {
+	goxdr.EmptyReadState
+}
+
+func(node voidNode) value() any {
+	return nil
+}
+
+type primitiveNode struct {
+	*goxdr.PrimitiveReadState
+	t *Type
+	name string
+}
+
+func newPrimitiveNode(t *Type, name string, context *goxdr.DecodeContext) *primitiveNode {
+	size := 4
+	if t.Kind == KindHyper || t.Kind == KindUnsignedHyper || t.Kind == KindDouble {
+		size = 8
+	}
+	state, _ := goxdr.NewPrimitiveReadState(size)
+	state.HandlerName = name
+	state.DecodeContext = context
+	return &primitiveNode {
+		PrimitiveReadState: state,
+		t: t,
+		name: name,
+	}
+}
+
+func(node *primitiveNode) EndPacket() (err error) {
+	err = node.PrimitiveReadState.EndPacket()
+	if err == nil {
+		switch node.t.Kind {
+			case KindBool:
+				_, err = node.AsBool()
+			case KindEnum:
+				if _, known := node.t.EnumName(node.AsInt()); !known {
+					err = &EnumValueError {
+						Enum: node.t.DisplayName(),
+						Value: node.AsInt(),
+					}
+				}
+		}
+	}
+	return goxdr.WrapDecodeError(err, node.name, 0)
+}
+
+func(node *primitiveNode) value() any {
+	switch node.t.Kind {
+		case KindInt, KindEnum:
+			return node.AsInt()
+		case KindUnsignedInt:
+			return node.AsUint()
+		case KindHyper:
+			return node.AsHyperInt()
+		case KindUnsignedHyper:
+			return node.AsHyperUint()
+		case KindFloat:
+			return node.AsFloat()
+		case KindDouble:
+			return node.AsDouble()
+		case KindBool:
+			return node.AsUint() != 0
+		default:
+			return nil
+	}
+}
+
+type bytesCollector struct {
+	bytes []byte
+}
+
+func(collector *bytesCollector) Update(bytes []byte) (int, bool) {
+	collector.bytes = append(collector.bytes, bytes...)
+	return len(bytes), false
+}
+
+func(collector *bytesCollector) EndPacket() error {
+	return nil
+}
+
+type opaqueNode struct {
+	goxdr.ReadState
+	collector *bytesCollector
+	asString bool
+}
+
+func newOpaqueNode(t *Type, name string, context *goxdr.DecodeContext) *opaqueNode {
+	node := &opaqueNode {
+		collector: &bytesCollector{},
+		asString: t.Kind == KindString,
+	}
+	fixedState := &goxdr.FixedLengthOpaqueReadState {
+		ExpectedLength: t.Length,
+		Handler: node.collector,
+		HandlerName: name,
+		DecodeContext: context,
+	}
+	if t.Kind == KindFixedOpaque {
+		node.ReadState = fixedState
+	} else {
+		primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+		node.ReadState = &goxdr.VariableLengthOpaqueReadState {
+			PrimitiveState: primitiveState,
+			FixedLengthState: fixedState,
+			MaxLength: t.Length,
+			DecodeContext: context,
+		}
+	}
+	return node
+}
+
+func(node *opaqueNode) value() any {
+	if node.asString {
+		return string(node.collector.bytes)
+	}
+	if node.collector.bytes == nil {
+		return []byte{}
+	}
+	return node.collector.bytes
+}
+
+type arrayNode struct {
+	goxdr.ReadState
+	elements []dynamicNode
+}
+
+func newArrayNode(t *Type, name string, context *goxdr.DecodeContext) *arrayNode {
+	node := &arrayNode{}
+	fixedState := &goxdr.FixedLengthArrayReadState[any] {
+		ExpectedLength: t.Length,
+		HandlerName: name,
+		DecodeContext: context,
+		HandlerFactory: func(index uint32, size uint32) (goxdr.TypedReadState[any], error) {
+			element, err := newDynamicNode(t.Element, "", context)
+			if err != nil {
+				return nil, err
+			}
+			node.elements = append(node.elements, element)
+			return element, nil
+		},
+	}
+	if t.Kind == KindFixedArray {
+		node.ReadState = fixedState
+	} else {
+		primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+		node.ReadState = &goxdr.VariableLengthArrayReadState[any] {
+			PrimitiveState: primitiveState,
+			FixedLengthState: fixedState,
+			MaxLength: t.Length,
+			DecodeContext: context,
+		}
+	}
+	return node
+}
+
+func(node *arrayNode) value() any {
+	values := make([]any, len(node.elements))
+	for index, element := range node.elements {
+		values[index] = element.value()
+	}
+	return values
+}
+
+type unionNode struct {
+	*goxdr.TaggedUnionReadState[any]
+	t *Type
+	arm dynamicNode
+}
+
+func newUnionNode(t *Type, name string, context *goxdr.DecodeContext) *unionNode {
+	primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+	node := &unionNode {
+		t: t,
+	}
+	node.TaggedUnionReadState = &goxdr.TaggedUnionReadState[any] {
+		PrimitiveState: primitiveState,
+		HandlerName: name,
+		SignedDiscriminant: t.SignedDiscriminant(),
+		DecodeContext: context,
+		HandlerFactory: func(discriminant uint32, _ uint32) (goxdr.TypedReadState[any], error) {
+			var armType *Type
+			var armName string
+			if t.Kind == KindOptional {
+				switch discriminant {
+					case 0:
+						armType = Void()
+					case 1:
+						armType = t.Element
+					default:
+						return nil, nil
+				}
+			} else {
+				arm := t.Arm(int32(discriminant))
+				if arm == nil {
+					return nil, nil
+				}
+				err := checkDiscriminant(t, int32(discriminant))
+				if err != nil {
+					return nil, err
+				}
+				armType = arm.Type
+				armName = arm.Name
+			}
+			var err error
+			node.arm, err = newDynamicNode(armType, armName, context)
+			if err != nil {
+				return nil, err
+			}
+			return node.arm, nil
+		},
+	}
+	return node
+}
+
+func checkDiscriminant(t *Type, discriminant int32) (err error) {
+	discriminantType, err := t.DiscriminantType()
+	if err != nil {
+		return
+	}
+	switch discriminantType.Kind {
+		case KindBool:
+			if discriminant != 0 && discriminant != 1 {
+				err = &goxdr.BoolError {
+					Value: uint32(discriminant),
+				}
+			}
+		case KindEnum:
+			if _, known := discriminantType.EnumName(discriminant); !known {
+				err = &EnumValueError {
+					Enum: discriminantType.DisplayName(),
+					Value: discriminant,
+				}
+			}
+	}
+	return
+}
+
+func(node *unionNode) value() any {
+	var armValue any
+	if node.arm != nil {
+		armValue = node.arm.value()
+	}
+	if node.t.Kind == KindOptional {
+		return armValue
+	}
+	var tag any
+	discriminant := node.PrimitiveState.AsUint()
+	switch node.t.Discriminant.Type.Resolve().Kind {
+		case KindUnsignedInt:
+			tag = discriminant
+		case KindBool:
+			tag = discriminant != 0
+		default:
+			tag = int32(discriminant)
+	}
+	return map[string]any {
+		UnionTagKey: tag,
+		UnionValueKey: armValue,
+	}
+}
+
+type structNode struct {
+	t *Type
+	name string
+	context *goxdr.DecodeContext
+	fields []dynamicNode
+	offset uint64
+	fieldOffset uint64
+	begun bool
+	firstError error
+}
+
+func(node *structNode) observe(kind goxdr.DecodeEventKind) {
+	if node.context.Observing() {
+		node.context.Observe(&goxdr.DecodeEvent {
+			Kind: kind,
+			Name: node.name,
+			Offset: node.context.ByteCount(),
+		})
+	}
+}
+
+func(node *structNode) begin() {
+	if !node.begun {
+		node.begun = true
+		node.observe(goxdr.EventBeginStruct)
+	}
+}
+
+func(node *structNode) currentField() (field dynamicNode, done bool) {
+	node.begin()
+	index := len(node.fields) - 1
+	if index >= 0 && index < len(node.t.Fields) {
+		return node.fields[index], false
+	}
+	return nil, true
+}
+
+func(node *structNode) nextField() bool {
+	index := len(node.fields)
+	if index >= len(node.t.Fields) {
+		node.fields = append(node.fields, nil)
+		node.begin()
+		node.observe(goxdr.EventEndStruct)
+		return false
+	}
+	node.fieldOffset = node.offset
+	field, err := newDynamicNode(node.t.Fields[index].Type, node.t.Fields[index].Name, node.context)
+	if err != nil {
+		node.firstError = goxdr.WrapDecodeError(err, node.name, node.fieldOffset)
+		return false
+	}
+	node.fields = append(node.fields, field)
+	return true
+}
+
+func(node *structNode) endField(field dynamicNode) bool {
+	err := field.EndPacket()
+	if err != nil {
+		node.firstError = goxdr.WrapDecodeError(err, node.name, node.fieldOffset)
+		return false
+	}
+	return node.nextField()
+}
+
+func(node *structNode) Update(bytes []byte) (readCount int, isFull bool) {
+	if node.firstError != nil {
+		isFull = true
+		return
+	}
+	if len(node.fields) == 0 && !node.nextField() {
+		isFull = true
+		return
+	}
+	for {
+		field, done := node.currentField()
+		if done {
+			isFull = true
+			return
+		}
+		var handled int
+		handled, isFull = field.Update(bytes[readCount:])
+		if handled < 0 || handled > len(bytes) - readCount {
+			node.firstError = goxdr.WrapDecodeError(&goxdr.OverreadError {
+				Subject: "Struct field read state",
+				ReadCount: handled,
+				Offered: len(bytes) - readCount,
+			}, node.name, node.fieldOffset)
+			isFull = true
+			return
+		}
+		readCount += handled
+		node.offset += uint64(handled)
+		if !isFull {
+			return
+		}
+		if !node.endField(field) {
+			isFull = true
+			return
+		}
+	}
+}
+
+func(node *structNode) EndPacket() error {
+	if node.firstError == nil && len(node.fields) == 0 {
+		node.nextField()
+	}
+	for node.firstError == nil {
+		field, done := node.currentField()
+		if done || !node.endField(field) {
+			break
+		}
+	}
+	return node.firstError
+}
+
+func(node *structNode) value() any {
+	values := make(map[string]any, len(node.t.Fields))
+	for index, field := range node.fields {
+		if field != nil && index < len(node.t.Fields) {
+			values[node.t.Fields[index].Name] = field.value()
+		}
+	}
+	return values
+}
+
+func newDynamicNode(t *Type, name string, context *goxdr.DecodeContext) (dynamicNode, error) {
+	resolved := t.Resolve()
+	if resolved == nil {
+		return nil, &UndefinedTypeError {
+			Name: t.Name,
+		}
+	}
+	switch resolved.Kind {
+		case KindVoid:
+			return voidNode{}, nil
+		case KindInt, KindUnsignedInt, KindHyper, KindUnsignedHyper, KindFloat, KindDouble, KindBool, KindEnum:
+			return newPrimitiveNode(resolved, name, context), nil
+		case KindFixedOpaque, KindVariableOpaque, KindString:
+			return newOpaqueNode(resolved, name, context), nil
+		case KindFixedArray, KindVariableArray:
+			return newArrayNode(resolved, name, context), nil
+		case KindOptional:
+			return newUnionNode(resolved, name, context), nil
+		case KindUnion:
+			_, err := resolved.DiscriminantType()
+			if err != nil {
+				return nil, err
+			}
+			return newUnionNode(resolved, name, context), nil
+		case KindStruct:
+			return &structNode {
+				t: resolved,
+				name: name,
+				context: context,
+			}, nil
+		default:
+			return nil, &UndefinedTypeError {
+				Name: resolved.DisplayName(),
+			}
+	}
+}
+
+type DynamicReadState struct {
+	Type *Type
+	Name string
+	DecodeContext *goxdr.DecodeContext
+	root dynamicNode
+	firstError error
+}
+
+func NewDynamicReadState(t *Type, context *goxdr.DecodeContext) (state *DynamicReadState, err error) {
+	if t.Resolve() == nil {
+		err = &UndefinedTypeError {
+			Name: t.Name,
+		}
+		return
+	}
+	state = &DynamicReadState {
+		Type: t,
+		DecodeContext: context,
+	}
+	return
+}
+
+func(state *DynamicReadState) InheritDecodeContext(context *goxdr.DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *DynamicReadState) Reset() {
+	state.root = nil
+	state.firstError = nil
+}
+
+func(state *DynamicReadState) ensureRoot() bool {
+	if state.root == nil && state.firstError == nil {
+		state.root, state.firstError = newDynamicNode(state.Type, state.Name, state.DecodeContext)
+	}
+	return state.firstError == nil
+}
+
+func(state *DynamicReadState) Update(bytes []byte) (readCount int, isFull bool) {
+	if !state.ensureRoot() {
+		isFull = true
+		return
+	}
+	return state.root.Update(bytes)
+}
+
+func(state *DynamicReadState) EndPacket() error {
+	if !state.ensureRoot() {
+		return state.firstError
+	}
+	return state.root.EndPacket()
+}
+
+func(state *DynamicReadState) Value() any {
+	if state.root == nil {
+		return nil
+	}
+	return state.root.value()
+}
+
+var _ goxdr.ContextualReadState = &DynamicReadState{}
@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"testing"
+	"github.com/UncleSniper/goxdr"
+)
+
+func TestEmptyStructEventsBalance(t *testing.T) {
+	var kinds []goxdr.DecodeEventKind
+	context := goxdr.NewDecodeContext(goxdr.DefaultLimits)
+	context.Observer = func(event *goxdr.DecodeEvent) {
+		kinds = append(kinds, event.Kind)
+	}
+	state, err := NewDynamicReadState(Struct("empty"), context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = goxdr.DecodeExact(state, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kinds) != 2 || kinds[0] != goxdr.EventBeginStruct || kinds[1] != goxdr.EventEndStruct {
+		t.Fatalf("events = %v, want BeginStruct, EndStruct", kinds)
+	}
+}
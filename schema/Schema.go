@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"sort"
+)
+
+const maxReferenceHops = 64
+
+type Schema struct {
+	Types map[string]*Type
+	Constants map[string]int64
+}
+
+func NewSchema() *Schema {
+	return &Schema {
+		Types: make(map[string]*Type),
+		Constants: map[string]int64 {
+			"FALSE": 0,
+			"TRUE": 1,
+		},
+	}
+}
+
+func(schema *Schema) Define(name string, t *Type) {
+	if len(t.Name) == 0 && t.Kind != KindReference {
+		t.Name = name
+	}
+	schema.Types[name] = t
+	if t.Kind == KindEnum {
+		for _, value := range t.Values {
+			schema.Constants[value.Name] = int64(value.Value)
+		}
+	}
+}
+
+func(schema *Schema) Lookup(name string) (t *Type, err error) {
+	t = schema.Types[name].Resolve()
+	if t == nil {
+		err = &UndefinedTypeError {
+			Name: name,
+		}
+	}
+	return
+}
+
+func(schema *Schema) Names() []string {
+	names := make([]string, 0, len(schema.Types))
+	for name := range schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func(schema *Schema) Resolve() error {
+	visited := make(map[*Type]bool)
+	for _, name := range schema.Names() {
+		err := schema.resolveType(schema.Types[name], visited)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func(schema *Schema) resolveType(t *Type, visited map[*Type]bool) (err error) {
+	if t == nil || visited[t] {
+		return
+	}
+	visited[t] = true
+	switch t.Kind {
+		case KindReference:
+			if t.Target == nil {
+				t.Target = schema.Types[t.Name]
+				if t.Target == nil {
+					err = &UndefinedTypeError {
+						Name: t.Name,
+					}
+					return
+				}
+			}
+			if t.Resolve() == nil {
+				err = &UndefinedTypeError {
+					Name: t.Name,
+				}
+				return
+			}
+			err = schema.resolveType(t.Target, visited)
+		case KindFixedArray, KindVariableArray, KindOptional:
+			err = schema.resolveType(t.Element, visited)
+		case KindStruct:
+			for _, field := range t.Fields {
+				err = schema.resolveType(field.Type, visited)
+				if err != nil {
+					return
+				}
+			}
+		case KindUnion:
+			if t.Discriminant != nil {
+				err = schema.resolveType(t.Discriminant.Type, visited)
+			}
+			for _, arm := range t.Arms {
+				if err != nil {
+					return
+				}
+				err = schema.resolveType(arm.Type, visited)
+			}
+			if err == nil && t.Default != nil {
+				err = schema.resolveType(t.Default.Type, visited)
+			}
+	}
+	return
+}
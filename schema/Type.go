@@ -0,0 +1,310 @@
+package schema
+
+import (
+	"math"
+)
+
+type Kind int
+
+const (
+	KindVoid Kind = iota
+	KindInt
+	KindUnsignedInt
+	KindHyper
+	KindUnsignedHyper
+	KindFloat
+	KindDouble
+	KindBool
+	KindEnum
+	KindFixedOpaque
+	KindVariableOpaque
+	KindString
+	KindFixedArray
+	KindVariableArray
+	KindOptional
+	KindStruct
+	KindUnion
+	KindReference
+)
+
+func(kind Kind) String() string {
+	switch kind {
+		case KindVoid:
+			return "void"
+		case KindInt:
+			return "int"
+		case KindUnsignedInt:
+			return "unsigned int"
+		case KindHyper:
+			return "hyper"
+		case KindUnsignedHyper:
+			return "unsigned hyper"
+		case KindFloat:
+			return "float"
+		case KindDouble:
+			return "double"
+		case KindBool:
+			return "bool"
+		case KindEnum:
+			return "enum"
+		case KindFixedOpaque:
+			return "fixed-length opaque"
+		case KindVariableOpaque:
+			return "variable-length opaque"
+		case KindString:
+			return "string"
+		case KindFixedArray:
+			return "fixed-length array"
+		case KindVariableArray:
+			return "variable-length array"
+		case KindOptional:
+			return "optional"
+		case KindStruct:
+			return "struct"
+		case KindUnion:
+			return "union"
+		case KindReference:
+			return "reference"
+		default:
+			return "unknown"
+	}
+}
+
+type Field struct {
+	Name string
+	Type *Type
+}
+
+type Arm struct {
+	Cases []int32
+	Field
+}
+
+type EnumValue struct {
+	Name string
+	Value int32
+}
+
+type Type struct {
+	Kind Kind
+	Name string
+	Length uint32
+	Element *Type
+	Fields []*Field
+	Values []EnumValue
+	Discriminant *Field
+	Arms []*Arm
+	Default *Arm
+	Target *Type
+}
+
+func(t *Type) Resolve() *Type {
+	for hops := 0; t != nil && t.Kind == KindReference; hops++ {
+		if hops > maxReferenceHops {
+			return nil
+		}
+		t = t.Target
+	}
+	return t
+}
+
+func(t *Type) EnumName(value int32) (string, bool) {
+	for _, enumValue := range t.Values {
+		if enumValue.Value == value {
+			return enumValue.Name, true
+		}
+	}
+	return "", false
+}
+
+func(t *Type) EnumValue(name string) (int32, bool) {
+	for _, enumValue := range t.Values {
+		if enumValue.Name == name {
+			return enumValue.Value, true
+		}
+	}
+	return 0, false
+}
+
+func(t *Type) Arm(discriminant int32) *Arm {
+	for _, arm := range t.Arms {
+		for _, value := range arm.Cases {
+			if value == discriminant {
+				return arm
+			}
+		}
+	}
+	return t.Default
+}
+
+func(t *Type) SignedDiscriminant() bool {
+	if t.Kind != KindUnion || t.Discriminant == nil {
+		return false
+	}
+	discriminantType := t.Discriminant.Type.Resolve()
+	return discriminantType != nil && (discriminantType.Kind == KindInt || discriminantType.Kind == KindEnum)
+}
+
+func(t *Type) DiscriminantType() (*Type, error) {
+	var discriminantType *Type
+	if t.Discriminant != nil {
+		discriminantType = t.Discriminant.Type.Resolve()
+	}
+	if discriminantType == nil {
+		return nil, &UndefinedTypeError {
+			Name: t.DisplayName() + " discriminant",
+		}
+	}
+	return discriminantType, nil
+}
+
+func(t *Type) DisplayName() string {
+	if len(t.Name) > 0 {
+		return t.Name
+	}
+	return t.Kind.String()
+}
+
+func Void() *Type {
+	return &Type {
+		Kind: KindVoid,
+	}
+}
+
+func Int() *Type {
+	return &Type {
+		Kind: KindInt,
+	}
+}
+
+func UnsignedInt() *Type {
+	return &Type {
+		Kind: KindUnsignedInt,
+	}
+}
+
+func Hyper() *Type {
+	return &Type {
+		Kind: KindHyper,
+	}
+}
+
+func UnsignedHyper() *Type {
+	return &Type {
+		Kind: KindUnsignedHyper,
+	}
+}
+
+func Float() *Type {
+	return &Type {
+		Kind: KindFloat,
+	}
+}
+
+func Double() *Type {
+	return &Type {
+		Kind: KindDouble,
+	}
+}
+
+func Bool() *Type {
+	return &Type {
+		Kind: KindBool,
+	}
+}
+
+func Enum(name string, values ...EnumValue) *Type {
+	return &Type {
+		Kind: KindEnum,
+		Name: name,
+		Values: values,
+	}
+}
+
+func FixedOpaque(length uint32) *Type {
+	return &Type {
+		Kind: KindFixedOpaque,
+		Length: length,
+	}
+}
+
+func VariableOpaque(maxLength uint32) *Type {
+	return &Type {
+		Kind: KindVariableOpaque,
+		Length: maxLength,
+	}
+}
+
+func String(maxLength uint32) *Type {
+	return &Type {
+		Kind: KindString,
+		Length: maxLength,
+	}
+}
+
+func FixedArray(element *Type, length uint32) *Type {
+	return &Type {
+		Kind: KindFixedArray,
+		Length: length,
+		Element: element,
+	}
+}
+
+func VariableArray(element *Type, maxLength uint32) *Type {
+	return &Type {
+		Kind: KindVariableArray,
+		Length: maxLength,
+		Element: element,
+	}
+}
+
+func Optional(element *Type) *Type {
+	return &Type {
+		Kind: KindOptional,
+		Element: element,
+	}
+}
+
+func Struct(name string, fields ...*Field) *Type {
+	return &Type {
+		Kind: KindStruct,
+		Name: name,
+		Fields: fields,
+	}
+}
+
+func Union(name string, discriminant *Field, defaultArm *Arm, arms ...*Arm) *Type {
+	return &Type {
+		Kind: KindUnion,
+		Name: name,
+		Discriminant: discriminant,
+		Arms: arms,
+		Default: defaultArm,
+	}
+}
+
+func Reference(name string) *Type {
+	return &Type {
+		Kind: KindReference,
+		Name: name,
+	}
+}
+
+func NewField(name string, t *Type) *Field {
+	return &Field {
+		Name: name,
+		Type: t,
+	}
+}
+
+func NewArm(name string, t *Type, cases ...int32) *Arm {
+	return &Arm {
+		Cases: cases,
+		Field: Field {
+			Name: name,
+			Type: t,
+		},
+	}
+}
+
+const Unbounded uint32 = math.MaxUint32
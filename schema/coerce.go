@@ -0,0 +1,236 @@
+package schema
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"encoding/json"
+)
+
+func joinValuePath(parent string, child string) string {
+	switch {
+		case len(parent) == 0:
+			return child
+		case len(child) == 0:
+			return parent
+		case child[0] == '[':
+			return parent + child
+		default:
+			return parent + "." + child
+	}
+}
+
+func indexPath(parent string, index int) string {
+	return parent + "[" + strconv.Itoa(index) + "]"
+}
+
+func valueError(path string, t *Type, value any, reason string) error {
+	return &ValueError {
+		Path: path,
+		Type: t,
+		Value: value,
+		Reason: reason,
+	}
+}
+
+func coerceInt64(value any) (result int64, ok bool) {
+	ok = true
+	switch typed := value.(type) {
+		case int:
+			result = int64(typed)
+		case int8:
+			result = int64(typed)
+		case int16:
+			result = int64(typed)
+		case int32:
+			result = int64(typed)
+		case int64:
+			result = typed
+		case uint:
+			ok = uint64(typed) <= math.MaxInt64
+			result = int64(typed)
+		case uint8:
+			result = int64(typed)
+		case uint16:
+			result = int64(typed)
+		case uint32:
+			result = int64(typed)
+		case uint64:
+			ok = typed <= math.MaxInt64
+			result = int64(typed)
+		case float32:
+			return coerceInt64(float64(typed))
+		case float64:
+			ok = typed == math.Trunc(typed) && typed >= math.MinInt64 && typed < math.MaxInt64
+			result = int64(typed)
+		case json.Number:
+			return coerceInt64(string(typed))
+		case string:
+			var err error
+			result, err = strconv.ParseInt(typed, 10, 64)
+			ok = err == nil
+		default:
+			ok = false
+	}
+	return
+}
+
+func coerceUint64(value any) (result uint64, ok bool) {
+	ok = true
+	switch typed := value.(type) {
+		case uint:
+			result = uint64(typed)
+		case uint8:
+			result = uint64(typed)
+		case uint16:
+			result = uint64(typed)
+		case uint32:
+			result = uint64(typed)
+		case uint64:
+			result = typed
+		case float32:
+			return coerceUint64(float64(typed))
+		case float64:
+			ok = typed == math.Trunc(typed) && typed >= 0 && typed < math.MaxUint64
+			result = uint64(typed)
+		case json.Number:
+			return coerceUint64(string(typed))
+		case string:
+			var err error
+			result, err = strconv.ParseUint(typed, 10, 64)
+			ok = err == nil
+		default:
+			var signed int64
+			signed, ok = coerceInt64(value)
+			ok = ok && signed >= 0
+			result = uint64(signed)
+	}
+	return
+}
+
+func coerceFloat64(value any) (result float64, ok bool) {
+	ok = true
+	switch typed := value.(type) {
+		case float32:
+			result = float64(typed)
+		case float64:
+			result = typed
+		case json.Number:
+			return coerceFloat64(string(typed))
+		case string:
+			var err error
+			result, err = strconv.ParseFloat(typed, 64)
+			ok = err == nil
+		default:
+			var signed int64
+			signed, ok = coerceInt64(value)
+			if ok {
+				result = float64(signed)
+			} else {
+				var unsigned uint64
+				unsigned, ok = coerceUint64(value)
+				result = float64(unsigned)
+			}
+	}
+	return
+}
+
+func coerceBool(value any) (result bool, ok bool) {
+	if typed, isBool := value.(bool); isBool {
+		return typed, true
+	}
+	if typed, isString := value.(string); isString {
+		switch typed {
+			case "TRUE", "true":
+				return true, true
+			case "FALSE", "false":
+				return false, true
+		}
+	}
+	digits, ok := coerceInt64(value)
+	if !ok || (digits != 0 && digits != 1) {
+		return false, false
+	}
+	return digits == 1, true
+}
+
+func coerceEnum(t *Type, value any) (result int32, ok bool) {
+	if name, isString := value.(string); isString {
+		result, ok = t.EnumValue(name)
+		if ok {
+			return
+		}
+	}
+	digits, ok := coerceInt64(value)
+	if !ok || digits < math.MinInt32 || digits > math.MaxInt32 {
+		return 0, false
+	}
+	result = int32(digits)
+	_, ok = t.EnumName(result)
+	return
+}
+
+func coerceBytes(value any) (result []byte, ok bool) {
+	switch typed := value.(type) {
+		case []byte:
+			return typed, true
+		case string:
+			return []byte(typed), true
+		default:
+			return nil, false
+	}
+}
+
+func coerceSequence(value any) (result []any, ok bool) {
+	if typed, isSlice := value.([]any); isSlice {
+		return typed, true
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+		case reflect.Slice, reflect.Array:
+			result = make([]any, reflected.Len())
+			for index := range result {
+				result[index] = reflected.Index(index).Interface()
+			}
+			return result, true
+		default:
+			return nil, false
+	}
+}
+
+func coerceMapping(value any) (result map[string]any, ok bool) {
+	if typed, isMap := value.(map[string]any); isMap {
+		return typed, true
+	}
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Map || reflected.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	result = make(map[string]any, reflected.Len())
+	iterator := reflected.MapRange()
+	for iterator.Next() {
+		result[iterator.Key().String()] = iterator.Value().Interface()
+	}
+	return result, true
+}
+
+func isAbsent(value any) bool {
+	if value == nil {
+		return true
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+		case reflect.Pointer:
+			return reflected.IsNil()
+		default:
+			return false
+	}
+}
+
+func dereference(value any) any {
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() == reflect.Pointer && !reflected.IsNil() {
+		return reflected.Elem().Interface()
+	}
+	return value
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestCoerceIntegersAreDecimal(t *testing.T) {
+	if value, ok := coerceInt64("010"); !ok || value != 10 {
+		t.Fatalf("coerceInt64(\"010\") = %d, %v, want 10", value, ok)
+	}
+	if value, ok := coerceUint64("010"); !ok || value != 10 {
+		t.Fatalf("coerceUint64(\"010\") = %d, %v, want 10", value, ok)
+	}
+	for _, text := range []string{"0x10", "0b1", "0o7", "1_000"} {
+		if _, ok := coerceInt64(text); ok {
+			t.Fatalf("coerceInt64(%q) accepted a non-decimal string", text)
+		}
+		if _, ok := coerceUint64(text); ok {
+			t.Fatalf("coerceUint64(%q) accepted a non-decimal string", text)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ParseError struct {
+	Line int
+	Column int
+	Message string
+}
+
+func(err *ParseError) Error() string {
+	return "Line " + strconv.Itoa(err.Line) + ", column " + strconv.Itoa(err.Column) + ": " + err.Message
+}
+
+type UndefinedTypeError struct {
+	Name string
+}
+
+func(err *UndefinedTypeError) Error() string {
+	return "Undefined type: " + err.Name
+}
+
+type EnumValueError struct {
+	Enum string
+	Value int32
+}
+
+func(err *EnumValueError) Error() string {
+	return "Value " + strconv.FormatInt(int64(err.Value), 10) + " is not a member of enum " + err.Enum
+}
+
+type ValueError struct {
+	Path string
+	Type *Type
+	Value any
+	Reason string
+}
+
+func(err *ValueError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Cannot encode ")
+	builder.WriteString(fmt.Sprintf("%T", err.Value))
+	builder.WriteString(" value as ")
+	builder.WriteString(err.Type.DisplayName())
+	if len(err.Path) > 0 {
+		builder.WriteString(" at ")
+		builder.WriteString(err.Path)
+	}
+	if len(err.Reason) > 0 {
+		builder.WriteString(": ")
+		builder.WriteString(err.Reason)
+	}
+	return builder.String()
+}
@@ -0,0 +1,55 @@
+package schema_test
+
+import (
+	"testing"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+	"github.com/UncleSniper/goxdr/xdrtest"
+)
+
+const fuzzSchema = xdrtest.RFCFileSchema + `
+union result switch (int status) {
+case 0:
+   file files<4>;
+case -1:
+   unsigned hyper code;
+default:
+   void;
+};
+struct listing {
+   result head;
+   opaque tag[3];
+   bool more;
+   listing *next;
+};
+`
+
+func FuzzDynamicReadState(f *testing.F) {
+	definitions, err := schema.Parse(fuzzSchema)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(xdrtest.RFCFileExample(), "file", uint8(3))
+	f.Add([]byte("\x00\x00\x00\x00\x00\x00\x00\x00abc\x00\x00\x00\x00\x01\x00\x00\x00\x00"), "listing", uint8(4))
+	f.Add([]byte("\xff\xff\xff\xff\x00\x00\x00\x00\x00\x00\x00\x2a"), "result", uint8(1))
+	f.Fuzz(func(t *testing.T, data []byte, name string, split uint8) {
+		root, err := definitions.Lookup(name)
+		if err != nil {
+			return
+		}
+		codec := xdrtest.NewSchemaCodec(root)
+		err = xdrtest.CheckChunkingInvariants(data, split, codec.NewReadState, codec.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := codec.NewReadState()
+		if goxdr.DecodeExact(state, data) != nil {
+			return
+		}
+		value := codec.Value(state)
+		err = xdrtest.RoundTrip(codec, value, xdrtest.NewGenerator(int64(split)).Rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package schema
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenPunctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+	column int
+}
+
+type lexer struct {
+	source string
+	position int
+	line int
+	column int
+}
+
+func isLetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char == '_'
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func(scanner *lexer) fail(message string) *ParseError {
+	return &ParseError {
+		Line: scanner.line,
+		Column: scanner.column,
+		Message: message,
+	}
+}
+
+func(scanner *lexer) peekByte(ahead int) byte {
+	if scanner.position + ahead < len(scanner.source) {
+		return scanner.source[scanner.position + ahead]
+	}
+	return 0
+}
+
+func(scanner *lexer) advance() {
+	if scanner.source[scanner.position] == '\n' {
+		scanner.line++
+		scanner.column = 1
+	} else {
+		scanner.column++
+	}
+	scanner.position++
+}
+
+func(scanner *lexer) skipSpace() *ParseError {
+	atLineStart := scanner.column == 1
+	for scanner.position < len(scanner.source) {
+		char := scanner.source[scanner.position]
+		switch {
+			case char == '\n':
+				scanner.advance()
+				atLineStart = true
+			case char == ' ' || char == '\t' || char == '\r' || char == '\f':
+				scanner.advance()
+			case char == '%' && atLineStart:
+				for scanner.position < len(scanner.source) && scanner.source[scanner.position] != '\n' {
+					scanner.advance()
+				}
+			case char == '/' && scanner.peekByte(1) == '/':
+				for scanner.position < len(scanner.source) && scanner.source[scanner.position] != '\n' {
+					scanner.advance()
+				}
+			case char == '/' && scanner.peekByte(1) == '*':
+				err := scanner.fail("Unterminated comment")
+				scanner.advance()
+				scanner.advance()
+				for {
+					if scanner.position >= len(scanner.source) {
+						return err
+					}
+					if scanner.source[scanner.position] == '*' && scanner.peekByte(1) == '/' {
+						scanner.advance()
+						scanner.advance()
+						break
+					}
+					scanner.advance()
+				}
+			default:
+				return nil
+		}
+	}
+	return nil
+}
+
+func(scanner *lexer) next() (tok token, err *ParseError) {
+	err = scanner.skipSpace()
+	if err != nil {
+		return
+	}
+	tok.line = scanner.line
+	tok.column = scanner.column
+	if scanner.position >= len(scanner.source) {
+		tok.kind = tokenEnd
+		return
+	}
+	start := scanner.position
+	char := scanner.source[start]
+	switch {
+		case isLetter(char):
+			tok.kind = tokenIdentifier
+			for scanner.position < len(scanner.source) {
+				char = scanner.source[scanner.position]
+				if !isLetter(char) && !isDigit(char) {
+					break
+				}
+				scanner.advance()
+			}
+		case isDigit(char) || (char == '-' && isDigit(scanner.peekByte(1))):
+			tok.kind = tokenNumber
+			scanner.advance()
+			for scanner.position < len(scanner.source) {
+				char = scanner.source[scanner.position]
+				if !isLetter(char) && !isDigit(char) {
+					break
+				}
+				scanner.advance()
+			}
+		default:
+			switch char {
+				case '{', '}', '[', ']', '<', '>', '(', ')', '=', ';', ',', ':', '*':
+					tok.kind = tokenPunctuation
+					scanner.advance()
+				default:
+					err = scanner.fail("Unexpected character '" + string(rune(char)) + "'")
+					return
+			}
+	}
+	tok.text = scanner.source[start:scanner.position]
+	return
+}
+
+func tokenize(source string) (tokens []token, err error) {
+	scanner := &lexer {
+		source: source,
+		line: 1,
+		column: 1,
+	}
+	for {
+		tok, parseErr := scanner.next()
+		if parseErr != nil {
+			err = parseErr
+			return
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEnd {
+			return
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package schema
+
+import (
+	"math"
+	"strconv"
+)
+
+var keywords = map[string]bool {
+	"bool": true,
+	"case": true,
+	"const": true,
+	"default": true,
+	"double": true,
+	"enum": true,
+	"float": true,
+	"hyper": true,
+	"int": true,
+	"opaque": true,
+	"program": true,
+	"quadruple": true,
+	"string": true,
+	"struct": true,
+	"switch": true,
+	"typedef": true,
+	"union": true,
+	"unsigned": true,
+	"version": true,
+	"void": true,
+}
+
+type parser struct {
+	tokens []token
+	position int
+	schema *Schema
+}
+
+func Parse(source string) (*Schema, error) {
+	schema := NewSchema()
+	err := ParseInto(schema, source)
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func ParseInto(schema *Schema, source string) (err error) {
+	var tokens []token
+	tokens, err = tokenize(source)
+	if err != nil {
+		return
+	}
+	state := &parser {
+		tokens: tokens,
+		schema: schema,
+	}
+	for state.peek().kind != tokenEnd {
+		err = state.parseDefinition()
+		if err != nil {
+			return
+		}
+	}
+	err = schema.Resolve()
+	return
+}
+
+func(state *parser) peek() token {
+	return state.tokens[state.position]
+}
+
+func(state *parser) advance() token {
+	tok := state.tokens[state.position]
+	if tok.kind != tokenEnd {
+		state.position++
+	}
+	return tok
+}
+
+func(state *parser) failAt(tok token, message string) *ParseError {
+	return &ParseError {
+		Line: tok.line,
+		Column: tok.column,
+		Message: message,
+	}
+}
+
+func describeToken(tok token) string {
+	if tok.kind == tokenEnd {
+		return "end of input"
+	}
+	return "'" + tok.text + "'"
+}
+
+func(state *parser) accept(text string) bool {
+	tok := state.peek()
+	if tok.kind != tokenEnd && tok.kind != tokenNumber && tok.text == text {
+		state.position++
+		return true
+	}
+	return false
+}
+
+func(state *parser) expect(text string) error {
+	if state.accept(text) {
+		return nil
+	}
+	tok := state.peek()
+	return state.failAt(tok, "Expected '" + text + "', but found " + describeToken(tok))
+}
+
+func(state *parser) expectIdentifier() (name string, err error) {
+	tok := state.peek()
+	if tok.kind != tokenIdentifier || keywords[tok.text] {
+		err = state.failAt(tok, "Expected identifier, but found " + describeToken(tok))
+		return
+	}
+	state.advance()
+	name = tok.text
+	return
+}
+
+func(state *parser) parseValue() (value int64, err error) {
+	tok := state.advance()
+	switch tok.kind {
+		case tokenNumber:
+			value, err = strconv.ParseInt(tok.text, 0, 64)
+			if err != nil {
+				err = state.failAt(tok, "Malformed number " + describeToken(tok))
+			}
+		case tokenIdentifier:
+			var known bool
+			value, known = state.schema.Constants[tok.text]
+			if !known {
+				err = state.failAt(tok, "Undefined constant " + describeToken(tok))
+			}
+		default:
+			err = state.failAt(tok, "Expected constant, but found " + describeToken(tok))
+	}
+	return
+}
+
+func(state *parser) parseLength() (length uint32, err error) {
+	tok := state.peek()
+	var value int64
+	value, err = state.parseValue()
+	if err == nil {
+		if value < 0 || value > math.MaxUint32 {
+			err = state.failAt(tok, "Length " + strconv.FormatInt(value, 10) + " is out of range")
+		} else {
+			length = uint32(value)
+		}
+	}
+	return
+}
+
+func(state *parser) parseCaseValue() (value int32, err error) {
+	tok := state.peek()
+	var wide int64
+	wide, err = state.parseValue()
+	if err == nil {
+		if wide < math.MinInt32 || wide > math.MaxUint32 {
+			err = state.failAt(tok, "Case value " + strconv.FormatInt(wide, 10) + " is out of range")
+		} else {
+			value = int32(wide)
+		}
+	}
+	return
+}
+
+func(state *parser) parseMaxLength() (length uint32, err error) {
+	length = Unbounded
+	if !state.accept(">") {
+		length, err = state.parseLength()
+		if err == nil {
+			err = state.expect(">")
+		}
+	}
+	return
+}
+
+func(state *parser) parseDefinition() (err error) {
+	tok := state.peek()
+	switch {
+		case state.accept("const"):
+			var name string
+			name, err = state.expectIdentifier()
+			if err == nil {
+				err = state.expect("=")
+			}
+			var value int64
+			if err == nil {
+				value, err = state.parseValue()
+			}
+			if err == nil {
+				state.schema.Constants[name] = value
+			}
+		case state.accept("typedef"):
+			var field *Field
+			field, err = state.parseDeclaration()
+			if err == nil && field.Type.Kind == KindVoid {
+				err = state.failAt(tok, "Cannot typedef void")
+			}
+			if err == nil {
+				state.schema.Define(field.Name, field.Type)
+			}
+		case state.accept("enum"), state.accept("struct"), state.accept("union"):
+			var name string
+			name, err = state.expectIdentifier()
+			var t *Type
+			if err == nil {
+				t, err = state.parseBody(tok.text)
+			}
+			if err == nil {
+				state.schema.Define(name, t)
+			}
+		case state.accept("program"):
+			err = state.skipProgram()
+			return
+		default:
+			err = state.failAt(tok, "Expected definition, but found " + describeToken(tok))
+	}
+	if err == nil {
+		err = state.expect(";")
+	}
+	return
+}
+
+func(state *parser) skipProgram() (err error) {
+	depth := 0
+	for {
+		tok := state.advance()
+		switch {
+			case tok.kind == tokenEnd:
+				return state.failAt(tok, "Unterminated program definition")
+			case tok.text == "{":
+				depth++
+			case tok.text == "}":
+				depth--
+			case tok.text == ";" && depth == 0:
+				return nil
+		}
+	}
+}
+
+func(state *parser) parseBody(keyword string) (*Type, error) {
+	switch keyword {
+		case "enum":
+			return state.parseEnumBody()
+		case "struct":
+			return state.parseStructBody()
+		default:
+			return state.parseUnionBody()
+	}
+}
+
+func(state *parser) parseEnumBody() (t *Type, err error) {
+	err = state.expect("{")
+	if err != nil {
+		return
+	}
+	t = Enum("")
+	for {
+		var name string
+		name, err = state.expectIdentifier()
+		if err != nil {
+			return
+		}
+		err = state.expect("=")
+		if err != nil {
+			return
+		}
+		var value int32
+		value, err = state.parseCaseValue()
+		if err != nil {
+			return
+		}
+		t.Values = append(t.Values, EnumValue {
+			Name: name,
+			Value: value,
+		})
+		state.schema.Constants[name] = int64(value)
+		if !state.accept(",") {
+			break
+		}
+	}
+	err = state.expect("}")
+	return
+}
+
+func(state *parser) parseStructBody() (t *Type, err error) {
+	err = state.expect("{")
+	if err != nil {
+		return
+	}
+	t = Struct("")
+	for !state.accept("}") {
+		var field *Field
+		field, err = state.parseDeclaration()
+		if err != nil {
+			return
+		}
+		err = state.expect(";")
+		if err != nil {
+			return
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	if len(t.Fields) == 0 {
+		err = state.failAt(state.tokens[state.position - 1], "Struct has no fields")
+	}
+	return
+}
+
+func(state *parser) parseUnionBody() (t *Type, err error) {
+	err = state.expect("switch")
+	if err == nil {
+		err = state.expect("(")
+	}
+	t = Union("", nil, nil)
+	if err == nil {
+		t.Discriminant, err = state.parseDeclaration()
+	}
+	if err == nil {
+		err = state.expect(")")
+	}
+	if err == nil {
+		err = state.expect("{")
+	}
+	for err == nil && !state.accept("}") {
+		arm := &Arm{}
+		if state.accept("default") {
+			if t.Default != nil {
+				err = state.failAt(state.tokens[state.position - 1], "Duplicate default arm")
+				return
+			}
+			err = state.expect(":")
+			t.Default = arm
+		} else {
+			for err == nil && state.accept("case") {
+				var value int32
+				value, err = state.parseCaseValue()
+				if err == nil {
+					err = state.expect(":")
+				}
+				arm.Cases = append(arm.Cases, value)
+			}
+			if err == nil && len(arm.Cases) == 0 {
+				tok := state.peek()
+				err = state.failAt(tok, "Expected 'case' or 'default', but found " + describeToken(tok))
+			}
+			t.Arms = append(t.Arms, arm)
+		}
+		var field *Field
+		if err == nil {
+			field, err = state.parseDeclaration()
+		}
+		if err == nil {
+			arm.Field = *field
+			err = state.expect(";")
+		}
+	}
+	return
+}
+
+func(state *parser) parseDeclaration() (field *Field, err error) {
+	field = &Field{}
+	if state.accept("void") {
+		field.Type = Void()
+		return
+	}
+	if state.accept("opaque") {
+		field.Name, err = state.expectIdentifier()
+		if err != nil {
+			return
+		}
+		var length uint32
+		switch {
+			case state.accept("["):
+				length, err = state.parseLength()
+				if err == nil {
+					err = state.expect("]")
+				}
+				field.Type = FixedOpaque(length)
+			case state.accept("<"):
+				length, err = state.parseMaxLength()
+				field.Type = VariableOpaque(length)
+			default:
+				tok := state.peek()
+				err = state.failAt(tok, "Expected '[' or '<', but found " + describeToken(tok))
+		}
+		return
+	}
+	if state.accept("string") {
+		field.Name, err = state.expectIdentifier()
+		if err == nil {
+			err = state.expect("<")
+		}
+		var length uint32
+		if err == nil {
+			length, err = state.parseMaxLength()
+		}
+		field.Type = String(length)
+		return
+	}
+	var t *Type
+	t, err = state.parseTypeSpecifier()
+	if err != nil {
+		return
+	}
+	if state.accept("*") {
+		field.Name, err = state.expectIdentifier()
+		field.Type = Optional(t)
+		return
+	}
+	field.Name, err = state.expectIdentifier()
+	if err != nil {
+		return
+	}
+	var length uint32
+	switch {
+		case state.accept("["):
+			length, err = state.parseLength()
+			if err == nil {
+				err = state.expect("]")
+			}
+			field.Type = FixedArray(t, length)
+		case state.accept("<"):
+			length, err = state.parseMaxLength()
+			field.Type = VariableArray(t, length)
+		default:
+			field.Type = t
+	}
+	return
+}
+
+func(state *parser) parseTypeSpecifier() (t *Type, err error) {
+	tok := state.peek()
+	switch {
+		case state.accept("unsigned"):
+			switch {
+				case state.accept("int"):
+					t = UnsignedInt()
+				case state.accept("hyper"):
+					t = UnsignedHyper()
+				default:
+					t = UnsignedInt()
+			}
+		case state.accept("int"):
+			t = Int()
+		case state.accept("hyper"):
+			t = Hyper()
+		case state.accept("float"):
+			t = Float()
+		case state.accept("double"):
+			t = Double()
+		case state.accept("bool"):
+			t = Bool()
+		case state.accept("quadruple"):
+			err = state.failAt(tok, "Quadruple-precision floating point is not supported")
+		case state.accept("enum"), state.accept("union"):
+			t, err = state.parseBody(tok.text)
+		case state.accept("struct"):
+			if state.peek().text == "{" {
+				t, err = state.parseStructBody()
+			} else {
+				var name string
+				name, err = state.expectIdentifier()
+				t = Reference(name)
+			}
+		default:
+			var name string
+			name, err = state.expectIdentifier()
+			if err != nil {
+				err = state.failAt(tok, "Expected type, but found " + describeToken(tok))
+			}
+			t = Reference(name)
+	}
+	return
+}
@@ -0,0 +1,205 @@
+package goxdr
+
+import (
+	"io"
+	"os"
+	"net"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type plainReader struct {
+	io.Reader
+}
+
+type plainWriter struct {
+	io.Writer
+}
+
+func transferPaths(source string, sink *bytes.Buffer) map[string]func() (io.Reader, io.Writer) {
+	return map[string]func() (io.Reader, io.Writer) {
+		"ReaderFrom": func() (io.Reader, io.Writer) {
+			return plainReader{strings.NewReader(source)}, sink
+		},
+		"WriterTo": func() (io.Reader, io.Writer) {
+			return strings.NewReader(source), plainWriter{sink}
+		},
+	}
+}
+
+func TestTransferOpaqueRejectsLongStreamCleanly(t *testing.T) {
+	var sink bytes.Buffer
+	for name, endpoints := range transferPaths("abcdefg", &sink) {
+		sink.Reset()
+		reader, writer := endpoints()
+		err := WriteFixedLengthOpaqueReader(reader, 5, make([]byte, 8), writer, ZeroBytePadder)
+		var mismatch *LengthMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Actual != 7 {
+			t.Fatalf("%s: got %v, want a length mismatch reporting 7 bytes", name, err)
+		}
+		if !strings.HasPrefix("abcde", sink.String()) {
+			t.Fatalf("%s: wrote %q, want nothing past the expected bytes", name, sink.String())
+		}
+	}
+}
+
+func TestTransferOpaquePadsExactStream(t *testing.T) {
+	var sink bytes.Buffer
+	for name, endpoints := range transferPaths("abc", &sink) {
+		for expected, want := range map[uint32]string {
+			3: "abc\x00",
+			6: "abc\x00\x00\x00\x00\x00",
+		} {
+			sink.Reset()
+			reader, writer := endpoints()
+			err := WriteFixedLengthOpaqueReader(reader, expected, make([]byte, 8), writer, ZeroBytePadder)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if sink.String() != want {
+				t.Fatalf("%s: wrote %q, want %q", name, sink.String(), want)
+			}
+		}
+	}
+}
+
+type readerFromSpy struct {
+	io.Writer
+	source io.Reader
+}
+
+func(spy *readerFromSpy) ReadFrom(source io.Reader) (int64, error) {
+	spy.source = source
+	return spy.Writer.(io.ReaderFrom).ReadFrom(source)
+}
+
+type writerToSpy struct {
+	*os.File
+	target io.Writer
+}
+
+func(spy *writerToSpy) Len() int {
+	info, _ := spy.File.Stat()
+	offset, _ := spy.File.Seek(0, io.SeekCurrent)
+	return int(info.Size() - offset)
+}
+
+func(spy *writerToSpy) WriteTo(target io.Writer) (int64, error) {
+	spy.target = target
+	return spy.File.WriteTo(target)
+}
+
+func sourceFile(t *testing.T, content string) *os.File {
+	name := t.TempDir() + "/source"
+	err := os.WriteFile(name, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		file.Close()
+	})
+	return file
+}
+
+func checkFileHandedOver(t *testing.T, spy *readerFromSpy, file *os.File) {
+	limited, ok := spy.source.(*io.LimitedReader)
+	if !ok || limited.R != file {
+		t.Fatalf("ReadFrom got %T, want an *io.LimitedReader over the source file", spy.source)
+	}
+}
+
+func TestTransferOpaqueFileToFile(t *testing.T) {
+	source := sourceFile(t, "hello world")
+	destination, err := os.Create(t.TempDir() + "/destination")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer destination.Close()
+	spy := &readerFromSpy {
+		Writer: destination,
+	}
+	err = WriteFixedLengthOpaqueReader(source, 11, make([]byte, 8), spy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFileHandedOver(t, spy, source)
+	written, err := os.ReadFile(destination.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != "hello world\x00" {
+		t.Fatalf("wrote %q", written)
+	}
+}
+
+func TestTransferOpaqueFileToSocket(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer connection.Close()
+		data, _ := io.ReadAll(connection)
+		received <- data
+	}()
+	connection, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := sourceFile(t, "hello world")
+	spy := &readerFromSpy {
+		Writer: connection,
+	}
+	err = WriteFixedLengthOpaqueReader(source, 11, make([]byte, 8), spy, nil)
+	connection.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFileHandedOver(t, spy, source)
+	if data := <-received; string(data) != "hello world\x00" {
+		t.Fatalf("peer received %q", data)
+	}
+}
+
+func TestTransferOpaqueUsesSourceWriterTo(t *testing.T) {
+	var sink bytes.Buffer
+	destination := plainWriter{&sink}
+	for expected, length := range map[uint32]int {
+		11: 12,
+		13: 16,
+	} {
+		sink.Reset()
+		spy := &writerToSpy {
+			File: sourceFile(t, "hello world"),
+		}
+		err := WriteFixedLengthOpaqueReader(spy, expected, make([]byte, 8), destination, ZeroBytePadder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spy.target != io.Writer(destination) {
+			t.Fatalf("WriteTo got %T, want the destination itself", spy.target)
+		}
+		if sink.Len() != length {
+			t.Fatalf("wrote %q", sink.String())
+		}
+	}
+	sink.Reset()
+	err := WriteFixedLengthOpaqueReader(sourceFile(t, "hello world"), 5, make([]byte, 8), destination, ZeroBytePadder)
+	var mismatch *LengthMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Actual != 11 || sink.Len() != 0 {
+		t.Fatalf("got %v after writing %q, want a mismatch reporting 11 bytes and no output", err, sink.String())
+	}
+}
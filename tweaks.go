@@ -1,4 +1,9 @@
 package goxdr
 
 const minBulkTransferBufferSize = 256
+const minScratchBufferSize = 8
 const zeroSliceSize = 64
+const minStreamBufferSize = 512
+const trailingDataPreviewSize = 16
+const defaultCoalescingBufferSize = 4096
+const minCoalescingBufferSize = 64
@@ -0,0 +1,257 @@
+package vectors
+
+import (
+	"math"
+	"strings"
+	"encoding/hex"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+const Version = "1.0.0"
+
+type Vector struct {
+	Name string
+	Type *schema.Type
+	Value any
+	Encoding []byte
+}
+
+func encoding(text string) []byte {
+	decoded, err := hex.DecodeString(strings.ReplaceAll(text, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+func vector(name string, t *schema.Type, value any, text string) Vector {
+	return Vector {
+		Name: name,
+		Type: t,
+		Value: value,
+		Encoding: encoding(text),
+	}
+}
+
+func primitiveVectors() []Vector {
+	return []Vector {
+		vector("int/zero", schema.Int(), int32(0), "00000000"),
+		vector("int/one", schema.Int(), int32(1), "00000001"),
+		vector("int/minus-one", schema.Int(), int32(-1), "ffffffff"),
+		vector("int/min", schema.Int(), int32(math.MinInt32), "80000000"),
+		vector("int/max", schema.Int(), int32(math.MaxInt32), "7fffffff"),
+		vector("unsigned-int/zero", schema.UnsignedInt(), uint32(0), "00000000"),
+		vector("unsigned-int/max", schema.UnsignedInt(), uint32(math.MaxUint32), "ffffffff"),
+		vector("unsigned-int/byte-order", schema.UnsignedInt(), uint32(0x01020304), "01020304"),
+		vector("hyper/zero", schema.Hyper(), int64(0), "00000000 00000000"),
+		vector("hyper/minus-one", schema.Hyper(), int64(-1), "ffffffff ffffffff"),
+		vector("hyper/min", schema.Hyper(), int64(math.MinInt64), "80000000 00000000"),
+		vector("hyper/max", schema.Hyper(), int64(math.MaxInt64), "7fffffff ffffffff"),
+		vector("unsigned-hyper/max", schema.UnsignedHyper(), uint64(math.MaxUint64), "ffffffff ffffffff"),
+		vector("unsigned-hyper/byte-order", schema.UnsignedHyper(), uint64(0x0102030405060708), "01020304 05060708"),
+		vector("float/zero", schema.Float(), float32(0), "00000000"),
+		vector("float/negative-zero", schema.Float(), float32(math.Copysign(0, -1)), "80000000"),
+		vector("float/one", schema.Float(), float32(1), "3f800000"),
+		vector("float/minus-one-and-a-half", schema.Float(), float32(-1.5), "bfc00000"),
+		vector("float/max", schema.Float(), float32(math.MaxFloat32), "7f7fffff"),
+		vector("float/smallest-normal", schema.Float(), math.Float32frombits(0x00800000), "00800000"),
+		vector("float/smallest-denormal", schema.Float(), math.Float32frombits(0x00000001), "00000001"),
+		vector("float/largest-denormal", schema.Float(), math.Float32frombits(0x007fffff), "007fffff"),
+		vector("float/positive-infinity", schema.Float(), float32(math.Inf(1)), "7f800000"),
+		vector("float/negative-infinity", schema.Float(), float32(math.Inf(-1)), "ff800000"),
+		vector("float/quiet-nan", schema.Float(), math.Float32frombits(0x7fc00000), "7fc00000"),
+		vector("float/signaling-nan", schema.Float(), math.Float32frombits(0x7f800001), "7f800001"),
+		vector("double/zero", schema.Double(), float64(0), "00000000 00000000"),
+		vector("double/negative-zero", schema.Double(), math.Copysign(0, -1), "80000000 00000000"),
+		vector("double/one", schema.Double(), float64(1), "3ff00000 00000000"),
+		vector("double/minus-one-and-a-half", schema.Double(), float64(-1.5), "bff80000 00000000"),
+		vector("double/max", schema.Double(), math.MaxFloat64, "7fefffff ffffffff"),
+		vector("double/smallest-normal", schema.Double(), math.Float64frombits(0x0010000000000000), "00100000 00000000"),
+		vector("double/smallest-denormal", schema.Double(), math.Float64frombits(1), "00000000 00000001"),
+		vector("double/largest-denormal", schema.Double(), math.Float64frombits(0x000fffffffffffff), "000fffff ffffffff"),
+		vector("double/positive-infinity", schema.Double(), math.Inf(1), "7ff00000 00000000"),
+		vector("double/negative-infinity", schema.Double(), math.Inf(-1), "fff00000 00000000"),
+		vector("double/quiet-nan", schema.Double(), math.Float64frombits(0x7ff8000000000000), "7ff80000 00000000"),
+		vector("double/signaling-nan", schema.Double(), math.Float64frombits(0x7ff0000000000001), "7ff00000 00000001"),
+		vector("bool/false", schema.Bool(), false, "00000000"),
+		vector("bool/true", schema.Bool(), true, "00000001"),
+	}
+}
+
+func colorType() *schema.Type {
+	return schema.Enum("color",
+		schema.EnumValue {
+			Name: "RED",
+			Value: 2,
+		},
+		schema.EnumValue {
+			Name: "YELLOW",
+			Value: 3,
+		},
+		schema.EnumValue {
+			Name: "BLUE",
+			Value: 5,
+		},
+		schema.EnumValue {
+			Name: "BELOW",
+			Value: -7,
+		},
+	)
+}
+
+func enumVectors() []Vector {
+	return []Vector {
+		vector("enum/first", colorType(), int32(2), "00000002"),
+		vector("enum/last", colorType(), int32(5), "00000005"),
+		vector("enum/negative", colorType(), int32(-7), "fffffff9"),
+	}
+}
+
+func opaqueVectors() []Vector {
+	return []Vector {
+		vector("fixed-opaque/length-0", schema.FixedOpaque(0), []byte{}, ""),
+		vector("fixed-opaque/length-1", schema.FixedOpaque(1), []byte{0xab}, "ab000000"),
+		vector("fixed-opaque/length-2", schema.FixedOpaque(2), []byte{0xab, 0xcd}, "abcd0000"),
+		vector("fixed-opaque/length-3", schema.FixedOpaque(3), []byte{0xab, 0xcd, 0xef}, "abcdef00"),
+		vector("fixed-opaque/length-4", schema.FixedOpaque(4), []byte{0xab, 0xcd, 0xef, 0x01}, "abcdef01"),
+		vector("fixed-opaque/length-5", schema.FixedOpaque(5), []byte{1, 2, 3, 4, 5}, "01020304 05000000"),
+		vector("variable-opaque/empty", schema.VariableOpaque(schema.Unbounded), []byte{}, "00000000"),
+		vector("variable-opaque/length-1", schema.VariableOpaque(8), []byte{0xff}, "00000001 ff000000"),
+		vector("variable-opaque/length-2", schema.VariableOpaque(8), []byte{0xff, 0xee}, "00000002 ffee0000"),
+		vector("variable-opaque/length-3", schema.VariableOpaque(8), []byte{0xff, 0xee, 0xdd}, "00000003 ffeedd00"),
+		vector("variable-opaque/length-4", schema.VariableOpaque(4), []byte{0xff, 0xee, 0xdd, 0xcc}, "00000004 ffeeddcc"),
+		vector("variable-opaque/length-5", schema.VariableOpaque(8), []byte{0, 0, 0, 0, 0}, "00000005 00000000 00000000"),
+		vector("string/empty", schema.String(schema.Unbounded), "", "00000000"),
+		vector("string/length-1", schema.String(16), "a", "00000001 61000000"),
+		vector("string/length-2", schema.String(16), "ab", "00000002 61620000"),
+		vector("string/length-3", schema.String(16), "abc", "00000003 61626300"),
+		vector("string/length-4", schema.String(4), "abcd", "00000004 61626364"),
+		vector("string/utf-8", schema.String(16), "été", "00000005 c3a974c3 a9000000"),
+	}
+}
+
+func arrayVectors() []Vector {
+	return []Vector {
+		vector("fixed-array/empty", schema.FixedArray(schema.Int(), 0), []any{}, ""),
+		vector("fixed-array/ints", schema.FixedArray(schema.Int(), 3), []any{int32(1), int32(-2), int32(3)},
+			"00000001 fffffffe 00000003"),
+		vector("fixed-array/hypers", schema.FixedArray(schema.UnsignedHyper(), 2), []any{uint64(1), uint64(2)},
+			"00000000 00000001 00000000 00000002"),
+		vector("fixed-array/strings", schema.FixedArray(schema.String(8), 2), []any{"a", "bcdef"},
+			"00000001 61000000 00000005 62636465 66000000"),
+		vector("variable-array/empty", schema.VariableArray(schema.Int(), schema.Unbounded), []any{}, "00000000"),
+		vector("variable-array/bools", schema.VariableArray(schema.Bool(), 4), []any{true, false, true},
+			"00000003 00000001 00000000 00000001"),
+		vector("variable-array/opaques", schema.VariableArray(schema.VariableOpaque(4), 2), []any{[]byte{1}, []byte{}},
+			"00000002 00000001 01000000 00000000"),
+		vector("variable-array/nested", schema.VariableArray(schema.VariableArray(schema.Int(), 2), 2),
+			[]any{[]any{int32(7)}, []any{}},
+			"00000002 00000001 00000007 00000000"),
+	}
+}
+
+func pointType() *schema.Type {
+	return schema.Struct("point",
+		schema.NewField("x", schema.Int()),
+		schema.NewField("y", schema.Int()),
+		schema.NewField("label", schema.String(8)),
+	)
+}
+
+func resultType() *schema.Type {
+	return schema.Union("result", schema.NewField("status", schema.Int()),
+		schema.NewArm("", schema.Void()),
+		schema.NewArm("value", schema.UnsignedInt(), 0),
+		schema.NewArm("message", schema.String(16), -1, -2),
+	)
+}
+
+func switchType() *schema.Type {
+	return schema.Union("switch", schema.NewField("on", schema.Bool()), nil,
+		schema.NewArm("level", schema.Hyper(), 1),
+		schema.NewArm("", schema.Void(), 0),
+	)
+}
+
+func listType() *schema.Type {
+	list := schema.Struct("list", schema.NewField("value", schema.Int()))
+	list.Fields = append(list.Fields, schema.NewField("next", schema.Optional(list)))
+	return list
+}
+
+func compositeVectors() []Vector {
+	return []Vector {
+		vector("struct/point", pointType(), map[string]any {
+			"x": int32(1),
+			"y": int32(-1),
+			"label": "pt",
+		}, "00000001 ffffffff 00000002 70740000"),
+		vector("struct/empty", schema.Struct("empty"), map[string]any{}, ""),
+		vector("union/int-case", resultType(), map[string]any {
+			"tag": int32(0),
+			"value": uint32(42),
+		}, "00000000 0000002a"),
+		vector("union/negative-case", resultType(), map[string]any {
+			"tag": int32(-2),
+			"value": "oops",
+		}, "fffffffe 00000004 6f6f7073"),
+		vector("union/default-void", resultType(), map[string]any {
+			"tag": int32(9),
+			"value": nil,
+		}, "00000009"),
+		vector("union/enum-discriminant", schema.Union("paint", schema.NewField("color", colorType()), nil,
+			schema.NewArm("shade", schema.UnsignedInt(), 2, 3),
+			schema.NewArm("", schema.Void(), 5, -7),
+		), map[string]any {
+			"tag": int32(3),
+			"value": uint32(1),
+		}, "00000003 00000001"),
+		vector("union/bool-true", switchType(), map[string]any {
+			"tag": true,
+			"value": int64(-2),
+		}, "00000001 ffffffff fffffffe"),
+		vector("union/bool-false-void", switchType(), map[string]any {
+			"tag": false,
+			"value": nil,
+		}, "00000000"),
+		vector("optional/absent", schema.Optional(schema.Int()), nil, "00000000"),
+		vector("optional/present", schema.Optional(schema.Int()), int32(5), "00000001 00000005"),
+		vector("optional/linked-list", listType(), map[string]any {
+			"value": int32(1),
+			"next": map[string]any {
+				"value": int32(2),
+				"next": nil,
+			},
+		}, "00000001 00000001 00000002 00000000"),
+	}
+}
+
+func rfcVectors() []Vector {
+	definitions, err := schema.Parse(RFCFileSchema)
+	if err != nil {
+		panic(err)
+	}
+	file, err := definitions.Lookup("file")
+	if err != nil {
+		panic(err)
+	}
+	return []Vector {
+		Vector {
+			Name: "rfc4506/file-example",
+			Type: file,
+			Value: RFCFileExampleValue(),
+			Encoding: RFCFileExample(),
+		},
+	}
+}
+
+func Vectors() []Vector {
+	var all []Vector
+	all = append(all, primitiveVectors()...)
+	all = append(all, enumVectors()...)
+	all = append(all, opaqueVectors()...)
+	all = append(all, arrayVectors()...)
+	all = append(all, compositeVectors()...)
+	all = append(all, rfcVectors()...)
+	return all
+}
@@ -0,0 +1,67 @@
+package vectors
+
+const RFCFileSchema = `
+const MAXUSERNAME = 32;     /* max length of a user name */
+const MAXFILELEN = 65535;   /* max length of a file      */
+const MAXNAMELEN = 255;     /* max length of a file name */
+
+/*
+ * Types of files:
+ */
+enum filekind {
+   TEXT = 0,       /* ascii data */
+   DATA = 1,       /* raw data   */
+   EXEC = 2        /* executable */
+};
+
+/*
+ * File information, per kind of file:
+ */
+union filetype switch (filekind kind) {
+case TEXT:
+   void;                           /* no extra information */
+case DATA:
+   string creator<MAXNAMELEN>;     /* data creator         */
+case EXEC:
+   string interpretor<MAXNAMELEN>; /* program interpretor  */
+};
+
+/*
+ * A complete file:
+ */
+struct file {
+   string filename<MAXNAMELEN>; /* name of file    */
+   filetype type;               /* info about file */
+   string owner<MAXUSERNAME>;   /* owner of file   */
+   opaque data<MAXFILELEN>;     /* file data       */
+};
+`
+
+func RFCFileExample() []byte {
+	return []byte {
+		0x00, 0x00, 0x00, 0x09,
+		's', 'i', 'l', 'l',
+		'y', 'p', 'r', 'o',
+		'g', 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x04,
+		'l', 'i', 's', 'p',
+		0x00, 0x00, 0x00, 0x04,
+		'j', 'o', 'h', 'n',
+		0x00, 0x00, 0x00, 0x06,
+		'(', 'q', 'u', 'i',
+		't', ')', 0x00, 0x00,
+	}
+}
+
+func RFCFileExampleValue() map[string]any {
+	return map[string]any {
+		"filename": "sillyprog",
+		"type": map[string]any {
+			"tag": int32(2),
+			"value": "lisp",
+		},
+		"owner": "john",
+		"data": []byte("(quit)"),
+	}
+}
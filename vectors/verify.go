@@ -0,0 +1,91 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+type VectorError struct {
+	Name string
+	Stage string
+	Expected []byte
+	Actual []byte
+	Cause error
+}
+
+func(err *VectorError) Error() string {
+	message := "Vector " + err.Name + " failed to " + err.Stage
+	if err.Cause != nil {
+		return message + ": " + err.Cause.Error()
+	}
+	return message + ": expected " + hex.EncodeToString(err.Expected) + ", got " + hex.EncodeToString(err.Actual)
+}
+
+func(err *VectorError) Unwrap() error {
+	return err.Cause
+}
+
+func encode(t *schema.Type, value any) (encoded []byte, err error) {
+	packet, err := schema.NewDynamicPacket(t, value)
+	if err != nil {
+		return
+	}
+	var buffer bytes.Buffer
+	err = packet.WriteTo(make([]byte, 8), &buffer)
+	encoded = buffer.Bytes()
+	return
+}
+
+func Verify(vector Vector) error {
+	encoded, err := encode(vector.Type, vector.Value)
+	if err == nil && !bytes.Equal(encoded, vector.Encoding) {
+		return &VectorError {
+			Name: vector.Name,
+			Stage: "encode",
+			Expected: vector.Encoding,
+			Actual: encoded,
+		}
+	}
+	if err != nil {
+		return &VectorError {
+			Name: vector.Name,
+			Stage: "encode",
+			Cause: err,
+		}
+	}
+	state, err := schema.NewDynamicReadState(vector.Type, nil)
+	if err == nil {
+		err = goxdr.DecodeExact(state, vector.Encoding)
+	}
+	if err == nil {
+		encoded, err = encode(vector.Type, state.Value())
+	}
+	if err != nil {
+		return &VectorError {
+			Name: vector.Name,
+			Stage: "decode",
+			Cause: err,
+		}
+	}
+	if !bytes.Equal(encoded, vector.Encoding) {
+		return &VectorError {
+			Name: vector.Name,
+			Stage: "decode",
+			Expected: vector.Encoding,
+			Actual: encoded,
+		}
+	}
+	return nil
+}
+
+func VerifyAll() error {
+	for _, vector := range Vectors() {
+		err := Verify(vector)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,9 +2,9 @@ package goxdr
 
 import (
 	"io"
-	"fmt"
+	"os"
 	"math"
-	"errors"
+	"context"
 )
 
 func writeRemainder(buffer []byte, writer io.Writer, remainder int) (err error) {
@@ -26,13 +26,63 @@ func WriteFixedLengthOpaquePacket(packet Packet, buffer []byte, writer io.Writer
 	return
 }
 
+func cancelled(ctx context.Context, subject string, count uint32) error {
+	done := ctx.Done()
+	if done == nil {
+		return nil
+	}
+	select {
+		case <-done:
+			return &CancelledError {
+				Subject: subject,
+				Count: uint64(count),
+				Cause: ctx.Err(),
+			}
+		default:
+			return nil
+	}
+}
+
 func WriteFixedLengthOpaqueReader(
 	reader io.Reader,
 	expectedSize uint32,
 	buffer []byte,
 	writer io.Writer,
 	padding BytePadder,
+) error {
+	return WriteFixedLengthOpaqueReaderContext(context.Background(), reader, expectedSize, buffer, writer, padding)
+}
+
+func WriteFixedLengthOpaqueReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+) error {
+	return WriteFixedLengthOpaqueReaderProgress(ctx, reader, expectedSize, buffer, writer, padding, nil)
+}
+
+func WriteFixedLengthOpaqueReaderProgress(
+	ctx context.Context,
+	reader io.Reader,
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+	progress ProgressFunc,
 ) (err error) {
+	if progress == nil && ctx.Done() == nil {
+		transferred, handled, transferErr := transferOpaque(reader, expectedSize, writer)
+		if handled {
+			err = transferErr
+			if err == nil {
+				err = finishOpaqueStream(transferred, expectedSize, buffer, writer, padding, nil)
+			}
+			return
+		}
+	}
 	var transferBuffer []byte
 	if len(buffer) >= minBulkTransferBufferSize {
 		transferBuffer = buffer
@@ -42,18 +92,26 @@ func WriteFixedLengthOpaqueReader(
 	var actualSize uint32
 	var readCount int
 	for {
+		err = cancelled(ctx, "bytes", actualSize)
+		if err != nil {
+			return
+		}
 		readCount, err = reader.Read(transferBuffer)
 		if int64(readCount) > int64(math.MaxUint32) {
-			err = errors.New(fmt.Sprintf("Read chunk of size %d, which exceeds the range of uint32", readCount))
+			err = &OverflowError {
+				Subject: "Total read size",
+				Base: uint64(actualSize),
+				Increment: uint64(readCount),
+			}
 			return
 		}
 		nextSize := actualSize + uint32(readCount)
 		if nextSize < actualSize {
-			err = errors.New(fmt.Sprintf(
-				"Chunk (of size %d) would cause total read size (%d before chunk) to exceed the range of uint32",
-				readCount,
-				actualSize,
-			))
+			err = &OverflowError {
+				Subject: "Total read size",
+				Base: uint64(actualSize),
+				Increment: uint64(readCount),
+			}
 			return
 		}
 		var writeErr error
@@ -65,20 +123,111 @@ func WriteFixedLengthOpaqueReader(
 			err = writeErr
 		}
 		actualSize = nextSize
+		if progress != nil && readCount > 0 && writeErr == nil {
+			progress(uint64(actualSize), uint64(expectedSize))
+		}
 		if eof || err != nil {
 			break
 		}
 	}
-	if err == nil && actualSize != expectedSize {
-		if padding != nil && actualSize < expectedSize {
-			err = padding(writer, expectedSize - actualSize)
+	if err == nil {
+		err = finishOpaqueStream(uint64(actualSize), expectedSize, buffer, writer, padding, progress)
+	}
+	return
+}
+
+func transferOpaque(reader io.Reader, expectedSize uint32, writer io.Writer) (actualSize uint64, handled bool, err error) {
+	if readerFrom, ok := writer.(io.ReaderFrom); ok {
+		var transferred int64
+		transferred, err = readerFrom.ReadFrom(&io.LimitedReader {
+			R: reader,
+			N: int64(expectedSize),
+		})
+		actualSize = uint64(transferred)
+		handled = true
+		if err == nil && actualSize == uint64(expectedSize) {
+			var excess uint64
+			excess, err = countExcess(reader)
+			actualSize += excess
+		}
+	} else if writerTo, ok := reader.(io.WriterTo); ok {
+		remaining, known := sourceRemaining(reader)
+		if !known {
+			return
+		}
+		handled = true
+		if remaining > int64(expectedSize) {
+			actualSize = uint64(remaining)
+			return
+		}
+		var transferred int64
+		transferred, err = writerTo.WriteTo(writer)
+		actualSize = uint64(transferred)
+	}
+	return
+}
+
+type lengthReader interface {
+	Len() int
+}
+
+func sourceRemaining(reader io.Reader) (remaining int64, known bool) {
+	switch source := reader.(type) {
+		case lengthReader:
+			return int64(source.Len()), true
+		case *os.File:
+			info, err := source.Stat()
+			if err != nil || !info.Mode().IsRegular() {
+				return
+			}
+			offset, err := source.Seek(0, io.SeekCurrent)
+			if err != nil || offset > info.Size() {
+				return
+			}
+			return info.Size() - offset, true
+	}
+	return
+}
+
+func countExcess(reader io.Reader) (excess uint64, err error) {
+	var probe [1]byte
+	_, err = io.ReadFull(reader, probe[:])
+	if err == io.EOF {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	var drained int64
+	drained, err = io.Copy(io.Discard, reader)
+	excess = uint64(drained) + 1
+	return
+}
+
+func finishOpaqueStream(
+	actualSize uint64,
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+	progress ProgressFunc,
+) (err error) {
+	if actualSize != uint64(expectedSize) {
+		if padding != nil && actualSize < uint64(expectedSize) {
+			err = padding(writer, expectedSize - uint32(actualSize))
+			if err == nil && progress != nil {
+				progress(uint64(expectedSize), uint64(expectedSize))
+			}
 		} else {
-			err = errors.New(fmt.Sprintf(
-				"Expected stream length (%d) does not match actual stream length (%d)",
-				expectedSize,
-				actualSize,
-			))
+			err = &LengthMismatchError {
+				Subject: "stream",
+				Expected: uint64(expectedSize),
+				Actual: actualSize,
+			}
 		}
+	} else if expectedSize == 0 && progress != nil {
+		progress(0, 0)
 	}
 	remainder := int(expectedSize % uint32(4))
 	if err == nil && remainder > 0 {
@@ -93,24 +242,38 @@ func WriteFixedLengthOpaqueGenerator(
 	buffer []byte,
 	writer io.Writer,
 	padding BytePadder,
+) error {
+	return WriteFixedLengthOpaqueGeneratorContext(context.Background(), generator, expectedSize, buffer, writer, padding)
+}
+
+func WriteFixedLengthOpaqueGeneratorContext(
+	ctx context.Context,
+	generator ByteGenerator,
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+) error {
+	return WriteFixedLengthOpaqueGeneratorProgress(ctx, generator, expectedSize, buffer, writer, padding, nil)
+}
+
+func WriteFixedLengthOpaqueGeneratorProgress(
+	ctx context.Context,
+	generator ByteGenerator,
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+	progress ProgressFunc,
 ) (err error) {
 	var counter countingWriter
 	counter.writer = writer
+	counter.ctx = ctx
+	counter.expected = expectedSize
+	counter.progress = progress
 	err = generator(buffer, &counter)
-	if err == nil && counter.count != expectedSize {
-		if padding != nil && counter.count < expectedSize {
-			err = padding(writer, expectedSize - counter.count)
-		} else {
-			err = errors.New(fmt.Sprintf(
-				"Expected stream length (%d) does not match actual stream length (%d)",
-				expectedSize,
-				counter.count,
-			))
-		}
-	}
-	remainder := int(expectedSize % uint32(4))
-	if err == nil && remainder > 0 {
-		err = writeRemainder(buffer, writer, remainder)
+	if err == nil {
+		err = finishOpaqueStream(uint64(counter.count), expectedSize, buffer, writer, padding, progress)
 	}
 	return
 }
@@ -118,7 +281,11 @@ func WriteFixedLengthOpaqueGenerator(
 func WriteVariableLengthOpaquePacket(packet Packet, maxSize uint32, buffer []byte, writer io.Writer) (err error) {
 	actualSize := packet.ByteSize()
 	if actualSize > maxSize {
-		err = errors.New(fmt.Sprintf("Packet length (%d) exceeds maximum length (%d)", actualSize, maxSize))
+		err = &MaxLengthError {
+			Subject: "Packet",
+			Maximum: maxSize,
+			Actual: actualSize,
+		}
 		return
 	}
 	err = WriteUint(actualSize, buffer, writer)
@@ -140,14 +307,54 @@ func WriteVariableLengthOpaqueReader(
 	buffer []byte,
 	writer io.Writer,
 	padding BytePadder,
+) error {
+	return WriteVariableLengthOpaqueReaderContext(
+		context.Background(),
+		reader,
+		expectedSize,
+		maxSize,
+		buffer,
+		writer,
+		padding,
+	)
+}
+
+func WriteVariableLengthOpaqueReaderContext(
+	ctx context.Context,
+	reader io.Reader,
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+) error {
+	return WriteVariableLengthOpaqueReaderProgress(ctx, reader, expectedSize, maxSize, buffer, writer, padding, nil)
+}
+
+func WriteVariableLengthOpaqueReaderProgress(
+	ctx context.Context,
+	reader io.Reader,
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+	progress ProgressFunc,
 ) (err error) {
 	if expectedSize > maxSize {
-		err = errors.New(fmt.Sprintf("Packet length (%d) exceeds maximum length (%d)", expectedSize, maxSize))
+		err = &MaxLengthError {
+			Subject: "Packet",
+			Maximum: maxSize,
+			Actual: expectedSize,
+		}
 		return
 	}
-	err = WriteUint(expectedSize, buffer, writer)
+	err = cancelled(ctx, "bytes", 0)
+	if err == nil {
+		err = WriteUint(expectedSize, buffer, writer)
+	}
 	if err == nil {
-		err = WriteFixedLengthOpaqueReader(reader, expectedSize, buffer, writer, padding)
+		err = WriteFixedLengthOpaqueReaderProgress(ctx, reader, expectedSize, buffer, writer, padding, progress)
 	}
 	return
 }
@@ -159,14 +366,54 @@ func WriteVariableLengthOpaqueGenerator(
 	buffer []byte,
 	writer io.Writer,
 	padding BytePadder,
+) error {
+	return WriteVariableLengthOpaqueGeneratorContext(
+		context.Background(),
+		generator,
+		expectedSize,
+		maxSize,
+		buffer,
+		writer,
+		padding,
+	)
+}
+
+func WriteVariableLengthOpaqueGeneratorContext(
+	ctx context.Context,
+	generator ByteGenerator,
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+) error {
+	return WriteVariableLengthOpaqueGeneratorProgress(ctx, generator, expectedSize, maxSize, buffer, writer, padding, nil)
+}
+
+func WriteVariableLengthOpaqueGeneratorProgress(
+	ctx context.Context,
+	generator ByteGenerator,
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding BytePadder,
+	progress ProgressFunc,
 ) (err error) {
 	if expectedSize > maxSize {
-		err = errors.New(fmt.Sprintf("Packet length (%d) exceeds maximum length (%d)", expectedSize, maxSize))
+		err = &MaxLengthError {
+			Subject: "Packet",
+			Maximum: maxSize,
+			Actual: expectedSize,
+		}
 		return
 	}
-	err = WriteUint(expectedSize, buffer, writer)
+	err = cancelled(ctx, "bytes", 0)
 	if err == nil {
-		WriteFixedLengthOpaqueGenerator(generator, expectedSize, buffer, writer, padding)
+		err = WriteUint(expectedSize, buffer, writer)
+	}
+	if err == nil {
+		err = WriteFixedLengthOpaqueGeneratorProgress(ctx, generator, expectedSize, buffer, writer, padding, progress)
 	}
 	return
 }
@@ -177,12 +424,31 @@ func WriteFixedLengthArrayGenerator[T any](
 	buffer []byte,
 	writer io.Writer,
 	padding ElementPadder[T],
+) error {
+	return WriteFixedLengthArrayGeneratorContext(context.Background(), generator, expectedSize, buffer, writer, padding)
+}
+
+func WriteFixedLengthArrayGeneratorContext[T any](
+	ctx context.Context,
+	generator PacketGenerator[T],
+	expectedSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding ElementPadder[T],
 ) (err error) {
 	var actualSize uint32
 	err = generator(func(packet TypedPacket[T]) error {
+		cancelErr := cancelled(ctx, "elements", actualSize)
+		if cancelErr != nil {
+			return cancelErr
+		}
 		actualSize++
 		if actualSize == 0 {
-			return errors.New("Generated element caused actual element count to exceed the range of uint32")
+			return &OverflowError {
+				Subject: "Generated element count",
+				Base: math.MaxUint32,
+				Increment: 1,
+			}
 		} else {
 			return packet.WriteTo(buffer, writer)
 		}
@@ -190,17 +456,21 @@ func WriteFixedLengthArrayGenerator[T any](
 	if err == nil && actualSize != expectedSize {
 		if padding != nil && actualSize < expectedSize {
 			for u := actualSize; u < expectedSize; u++ {
+				err = cancelled(ctx, "elements", u)
+				if err != nil {
+					return
+				}
 				err = padding(buffer, writer)
 				if err != nil {
 					return
 				}
 			}
 		} else {
-			err = errors.New(fmt.Sprintf(
-				"Expected array length (%d) does not match actual array length (%d)",
-				expectedSize,
-				actualSize,
-			))
+			err = &LengthMismatchError {
+				Subject: "array",
+				Expected: uint64(expectedSize),
+				Actual: uint64(actualSize),
+			}
 		}
 	}
 	return
@@ -213,18 +483,116 @@ func WriteVariableLengthArrayGenerator[T any](
 	buffer []byte,
 	writer io.Writer,
 	padding ElementPadder[T],
+) error {
+	return WriteVariableLengthArrayGeneratorContext(
+		context.Background(),
+		generator,
+		expectedSize,
+		maxSize,
+		buffer,
+		writer,
+		padding,
+	)
+}
+
+func WriteVariableLengthArrayGeneratorContext[T any](
+	ctx context.Context,
+	generator PacketGenerator[T],
+	expectedSize uint32,
+	maxSize uint32,
+	buffer []byte,
+	writer io.Writer,
+	padding ElementPadder[T],
 ) (err error) {
 	if expectedSize > maxSize {
-		err = errors.New(fmt.Sprintf(
-			"Expected element count (%d) exceeds maximum count (%d)",
-			expectedSize,
-			maxSize,
-		))
+		err = &MaxLengthError {
+			Subject: "Array",
+			Maximum: maxSize,
+			Actual: expectedSize,
+		}
+		return
+	}
+	err = cancelled(ctx, "elements", 0)
+	if err == nil {
+		err = WriteUint(expectedSize, buffer, writer)
+	}
+	if err == nil {
+		err = WriteFixedLengthArrayGeneratorContext(ctx, generator, expectedSize, buffer, writer, padding)
+	}
+	return
+}
+
+func sliceLength(length int) (size uint32, err error) {
+	if int64(length) > int64(math.MaxUint32) {
+		err = &OverflowError {
+			Subject: "Slice element count",
+			Base: math.MaxUint32,
+			Increment: uint64(length) - math.MaxUint32,
+		}
+		return
+	}
+	size = uint32(length)
+	return
+}
+
+func WriteArray[T any](
+	elements []T,
+	expectedSize uint32,
+	encode ElementWriter[T],
+	buffer []byte,
+	writer io.Writer,
+) (err error) {
+	if uint64(len(elements)) != uint64(expectedSize) {
+		err = &LengthMismatchError {
+			Subject: "array",
+			Expected: uint64(expectedSize),
+			Actual: uint64(len(elements)),
+		}
+		return
+	}
+	for _, element := range elements {
+		err = encode(element, buffer, writer)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func WriteVarArray[T any](
+	elements []T,
+	maxSize uint32,
+	encode ElementWriter[T],
+	buffer []byte,
+	writer io.Writer,
+) (err error) {
+	actualSize, err := sliceLength(len(elements))
+	if err != nil {
 		return
 	}
-	err = WriteUint(expectedSize, buffer, writer)
+	if actualSize > maxSize {
+		err = &MaxLengthError {
+			Subject: "Array",
+			Maximum: maxSize,
+			Actual: actualSize,
+		}
+		return
+	}
+	err = WriteUint(actualSize, buffer, writer)
 	if err == nil {
-		err = WriteFixedLengthArrayGenerator(generator, expectedSize, buffer, writer, padding)
+		err = WriteArray(elements, actualSize, encode, buffer, writer)
+	}
+	return
+}
+
+func WriteUnion(discriminant uint32, arm Packet, cases *UnionCases, buffer []byte, writer io.Writer) (err error) {
+	err = cases.Check(discriminant)
+	if err != nil {
+		return
+	}
+	err = WriteUint(discriminant, buffer, writer)
+	if err == nil && arm != nil {
+		err = arm.WriteTo(buffer, writer)
 	}
 	return
 }
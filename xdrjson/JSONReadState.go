@@ -0,0 +1,511 @@
+package xdrjson
+
+import (
+	"io"
+	"strconv"
+	"encoding/base64"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+type voidNode struct {
+	out *output
+	written bool
+}
+
+func(node *voidNode) Update([]byte) (int, bool) {
+	return 0, true
+}
+
+func(node *voidNode) EndPacket() error {
+	if !node.written {
+		node.written = true
+		node.out.raw("null")
+	}
+	return nil
+}
+
+type primitiveNode struct {
+	*goxdr.PrimitiveReadState
+	t *schema.Type
+	name string
+	out *output
+}
+
+func newPrimitiveNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) *primitiveNode {
+	size := 4
+	if t.Kind == schema.KindHyper || t.Kind == schema.KindUnsignedHyper || t.Kind == schema.KindDouble {
+		size = 8
+	}
+	state, _ := goxdr.NewPrimitiveReadState(size)
+	state.HandlerName = name
+	state.DecodeContext = context
+	return &primitiveNode {
+		PrimitiveReadState: state,
+		t: t,
+		name: name,
+		out: out,
+	}
+}
+
+func(node *primitiveNode) EndPacket() error {
+	err := node.PrimitiveReadState.EndPacket()
+	if err == nil {
+		err = writePrimitive(node.t, node.PrimitiveReadState, node.out)
+	}
+	return goxdr.WrapDecodeError(err, node.name, 0)
+}
+
+func writePrimitive(t *schema.Type, state *goxdr.PrimitiveReadState, out *output) (err error) {
+	switch t.Kind {
+		case schema.KindInt:
+			out.raw(strconv.FormatInt(int64(state.AsInt()), 10))
+		case schema.KindUnsignedInt:
+			out.raw(strconv.FormatUint(uint64(state.AsUint()), 10))
+		case schema.KindHyper:
+			out.quoted(strconv.FormatInt(state.AsHyperInt(), 10))
+		case schema.KindUnsignedHyper:
+			out.quoted(strconv.FormatUint(state.AsHyperUint(), 10))
+		case schema.KindFloat, schema.KindDouble:
+			var text string
+			var quoted bool
+			if t.Kind == schema.KindFloat {
+				text, quoted = formatFloat(float64(state.AsFloat()), 32)
+			} else {
+				text, quoted = formatFloat(state.AsDouble(), 64)
+			}
+			if quoted {
+				out.quoted(text)
+			} else {
+				out.raw(text)
+			}
+		case schema.KindBool:
+			var value bool
+			value, err = state.AsBool()
+			if err == nil {
+				out.raw(strconv.FormatBool(value))
+			}
+		case schema.KindEnum:
+			name, known := t.EnumName(state.AsInt())
+			if known {
+				out.quoted(name)
+			} else {
+				err = &schema.EnumValueError {
+					Enum: t.DisplayName(),
+					Value: state.AsInt(),
+				}
+			}
+	}
+	return
+}
+
+type base64Handler struct {
+	out *output
+	encoder io.WriteCloser
+}
+
+func(handler *base64Handler) Update(bytes []byte) (int, bool) {
+	if handler.encoder == nil {
+		handler.out.raw("\"")
+		handler.encoder = base64.NewEncoder(base64.StdEncoding, handler.out)
+	}
+	handler.encoder.Write(bytes)
+	return len(bytes), false
+}
+
+func(handler *base64Handler) EndPacket() error {
+	if handler.encoder == nil {
+		handler.out.raw("\"\"")
+	} else {
+		handler.encoder.Close()
+		handler.out.raw("\"")
+	}
+	return nil
+}
+
+type stringHandler struct {
+	out *output
+	bytes []byte
+}
+
+func(handler *stringHandler) Update(bytes []byte) (int, bool) {
+	handler.bytes = append(handler.bytes, bytes...)
+	return len(bytes), false
+}
+
+func(handler *stringHandler) EndPacket() error {
+	handler.out.quoted(string(handler.bytes))
+	return nil
+}
+
+func newOpaqueNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) goxdr.ReadState {
+	var handler goxdr.ReadState
+	if t.Kind == schema.KindString {
+		handler = &stringHandler {
+			out: out,
+		}
+	} else {
+		handler = &base64Handler {
+			out: out,
+		}
+	}
+	fixedState := &goxdr.FixedLengthOpaqueReadState {
+		ExpectedLength: t.Length,
+		Handler: handler,
+		HandlerName: name,
+		DecodeContext: context,
+	}
+	if t.Kind == schema.KindFixedOpaque {
+		return fixedState
+	}
+	primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+	return &goxdr.VariableLengthOpaqueReadState {
+		PrimitiveState: primitiveState,
+		FixedLengthState: fixedState,
+		MaxLength: t.Length,
+		DecodeContext: context,
+	}
+}
+
+type containerNode struct {
+	goxdr.ReadState
+	out *output
+	opening string
+	closing string
+	begun bool
+	closed bool
+}
+
+func(node *containerNode) begin() {
+	if !node.begun {
+		node.begun = true
+		node.out.raw(node.opening)
+	}
+}
+
+func(node *containerNode) Update(bytes []byte) (int, bool) {
+	node.begin()
+	return node.ReadState.Update(bytes)
+}
+
+func(node *containerNode) EndPacket() error {
+	node.begin()
+	err := node.ReadState.EndPacket()
+	if err == nil && !node.closed {
+		node.closed = true
+		node.out.raw(node.closing)
+	}
+	return err
+}
+
+func newArrayNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) goxdr.ReadState {
+	fixedState := &goxdr.FixedLengthArrayReadState[any] {
+		ExpectedLength: t.Length,
+		HandlerName: name,
+		DecodeContext: context,
+		HandlerFactory: func(index uint32, size uint32) (goxdr.TypedReadState[any], error) {
+			if index > 0 {
+				out.raw(",")
+			}
+			return newNode(t.Element, "", out, context)
+		},
+	}
+	node := &containerNode {
+		ReadState: fixedState,
+		out: out,
+		opening: "[",
+		closing: "]",
+	}
+	if t.Kind == schema.KindVariableArray {
+		primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+		node.ReadState = &goxdr.VariableLengthArrayReadState[any] {
+			PrimitiveState: primitiveState,
+			FixedLengthState: fixedState,
+			MaxLength: t.Length,
+			DecodeContext: context,
+		}
+	}
+	return node
+}
+
+func newOptionalNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) goxdr.ReadState {
+	primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+	return &goxdr.TaggedUnionReadState[any] {
+		PrimitiveState: primitiveState,
+		HandlerName: name,
+		DecodeContext: context,
+		HandlerFactory: func(discriminant uint32, _ uint32) (goxdr.TypedReadState[any], error) {
+			switch discriminant {
+				case 0:
+					return &voidNode {
+						out: out,
+					}, nil
+				case 1:
+					return newNode(t.Element, "", out, context)
+				default:
+					return nil, nil
+			}
+		},
+	}
+}
+
+func writeDiscriminant(t *schema.Type, discriminant uint32, out *output) (err error) {
+	switch t.Kind {
+		case schema.KindUnsignedInt:
+			out.raw(strconv.FormatUint(uint64(discriminant), 10))
+		case schema.KindBool:
+			switch discriminant {
+				case 0:
+					out.raw("false")
+				case 1:
+					out.raw("true")
+				default:
+					err = &goxdr.BoolError {
+						Value: discriminant,
+					}
+			}
+		case schema.KindEnum:
+			name, known := t.EnumName(int32(discriminant))
+			if known {
+				out.quoted(name)
+			} else {
+				err = &schema.EnumValueError {
+					Enum: t.DisplayName(),
+					Value: int32(discriminant),
+				}
+			}
+		default:
+			out.raw(strconv.FormatInt(int64(int32(discriminant)), 10))
+	}
+	return
+}
+
+func newUnionNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) goxdr.ReadState {
+	primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+	return &containerNode {
+		ReadState: &goxdr.TaggedUnionReadState[any] {
+			PrimitiveState: primitiveState,
+			HandlerName: name,
+			SignedDiscriminant: t.SignedDiscriminant(),
+			DecodeContext: context,
+			HandlerFactory: func(discriminant uint32, _ uint32) (goxdr.TypedReadState[any], error) {
+				arm := t.Arm(int32(discriminant))
+				if arm == nil {
+					return nil, nil
+				}
+				out.quoted(schema.UnionTagKey)
+				out.raw(":")
+				err := writeDiscriminant(t.Discriminant.Type.Resolve(), discriminant, out)
+				if err != nil {
+					return nil, err
+				}
+				out.raw(",")
+				out.quoted(schema.UnionValueKey)
+				out.raw(":")
+				return newNode(arm.Type, arm.Name, out, context)
+			},
+		},
+		out: out,
+		opening: "{",
+		closing: "}",
+	}
+}
+
+type structNode struct {
+	t *schema.Type
+	name string
+	out *output
+	context *goxdr.DecodeContext
+	field goxdr.ReadState
+	index int
+	offset uint64
+	fieldOffset uint64
+	begun bool
+	done bool
+	firstError error
+}
+
+func(node *structNode) begin() {
+	if node.begun {
+		return
+	}
+	node.begun = true
+	node.index = -1
+	node.out.raw("{")
+	node.nextField()
+}
+
+func(node *structNode) nextField() {
+	node.index++
+	if node.index >= len(node.t.Fields) {
+		node.done = true
+		node.field = nil
+		node.out.raw("}")
+		return
+	}
+	if node.index > 0 {
+		node.out.raw(",")
+	}
+	field := node.t.Fields[node.index]
+	node.out.quoted(field.Name)
+	node.out.raw(":")
+	node.fieldOffset = node.offset
+	var err error
+	node.field, err = newNode(field.Type, field.Name, node.out, node.context)
+	if err != nil {
+		node.firstError = goxdr.WrapDecodeError(err, node.name, node.fieldOffset)
+	}
+}
+
+func(node *structNode) endField() bool {
+	err := node.field.EndPacket()
+	if err != nil {
+		node.firstError = goxdr.WrapDecodeError(err, node.name, node.fieldOffset)
+		return false
+	}
+	node.nextField()
+	return node.firstError == nil
+}
+
+func(node *structNode) Update(bytes []byte) (readCount int, isFull bool) {
+	node.begin()
+	for node.firstError == nil && !node.done {
+		handled, fieldFull := node.field.Update(bytes[readCount:])
+		if handled < 0 || handled > len(bytes) - readCount {
+			node.firstError = goxdr.WrapDecodeError(&goxdr.OverreadError {
+				Subject: "Struct field read state",
+				ReadCount: handled,
+				Offered: len(bytes) - readCount,
+			}, node.name, node.fieldOffset)
+			break
+		}
+		readCount += handled
+		node.offset += uint64(handled)
+		if !fieldFull {
+			return
+		}
+		node.endField()
+	}
+	isFull = true
+	return
+}
+
+func(node *structNode) EndPacket() error {
+	node.begin()
+	for node.firstError == nil && !node.done && node.endField() {
+	}
+	return node.firstError
+}
+
+func newNode(t *schema.Type, name string, out *output, context *goxdr.DecodeContext) (goxdr.ReadState, error) {
+	resolved := t.Resolve()
+	if resolved == nil {
+		return nil, &schema.UndefinedTypeError {
+			Name: t.Name,
+		}
+	}
+	switch resolved.Kind {
+		case schema.KindVoid:
+			return &voidNode {
+				out: out,
+			}, nil
+		case schema.KindInt, schema.KindUnsignedInt, schema.KindHyper, schema.KindUnsignedHyper,
+				schema.KindFloat, schema.KindDouble, schema.KindBool, schema.KindEnum:
+			return newPrimitiveNode(resolved, name, out, context), nil
+		case schema.KindFixedOpaque, schema.KindVariableOpaque, schema.KindString:
+			return newOpaqueNode(resolved, name, out, context), nil
+		case schema.KindFixedArray, schema.KindVariableArray:
+			return newArrayNode(resolved, name, out, context), nil
+		case schema.KindOptional:
+			return newOptionalNode(resolved, name, out, context), nil
+		case schema.KindUnion:
+			_, err := resolved.DiscriminantType()
+			if err != nil {
+				return nil, err
+			}
+			return newUnionNode(resolved, name, out, context), nil
+		case schema.KindStruct:
+			return &structNode {
+				t: resolved,
+				name: name,
+				out: out,
+				context: context,
+			}, nil
+		default:
+			return nil, &schema.UndefinedTypeError {
+				Name: resolved.DisplayName(),
+			}
+	}
+}
+
+type JSONReadState struct {
+	Type *schema.Type
+	Name string
+	Writer io.Writer
+	DecodeContext *goxdr.DecodeContext
+	root goxdr.ReadState
+	out *output
+	firstError error
+}
+
+func NewJSONReadState(t *schema.Type, writer io.Writer, context *goxdr.DecodeContext) (state *JSONReadState, err error) {
+	if t.Resolve() == nil {
+		err = &schema.UndefinedTypeError {
+			Name: t.Name,
+		}
+		return
+	}
+	state = &JSONReadState {
+		Type: t,
+		Writer: writer,
+		DecodeContext: context,
+	}
+	return
+}
+
+func(state *JSONReadState) InheritDecodeContext(context *goxdr.DecodeContext) {
+	if state.DecodeContext == nil {
+		state.DecodeContext = context
+	}
+}
+
+func(state *JSONReadState) Reset() {
+	state.root = nil
+	state.out = nil
+	state.firstError = nil
+}
+
+func(state *JSONReadState) ensureRoot() bool {
+	if state.root == nil && state.firstError == nil {
+		state.out = &output {
+			writer: state.Writer,
+		}
+		state.root, state.firstError = newNode(state.Type, state.Name, state.out, state.DecodeContext)
+	}
+	return state.firstError == nil
+}
+
+func(state *JSONReadState) Update(bytes []byte) (readCount int, isFull bool) {
+	if !state.ensureRoot() {
+		isFull = true
+		return
+	}
+	readCount, isFull = state.root.Update(bytes)
+	if state.out.err != nil {
+		isFull = true
+	}
+	return
+}
+
+func(state *JSONReadState) EndPacket() error {
+	if !state.ensureRoot() {
+		return state.firstError
+	}
+	err := state.root.EndPacket()
+	if err == nil {
+		err = state.out.err
+	}
+	return err
+}
+
+var _ goxdr.ContextualReadState = &JSONReadState{}
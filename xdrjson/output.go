@@ -0,0 +1,68 @@
+package xdrjson
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"encoding/json"
+)
+
+const NaN = "NaN"
+const PositiveInfinity = "Infinity"
+const NegativeInfinity = "-Infinity"
+
+type output struct {
+	writer io.Writer
+	err error
+}
+
+func(out *output) Write(bytes []byte) (writeCount int, err error) {
+	if out.err != nil {
+		return 0, out.err
+	}
+	writeCount, out.err = out.writer.Write(bytes)
+	return writeCount, out.err
+}
+
+func(out *output) raw(text string) {
+	if out.err == nil {
+		_, out.err = io.WriteString(out.writer, text)
+	}
+}
+
+func(out *output) quoted(text string) {
+	encoded, err := json.Marshal(text)
+	if err != nil {
+		if out.err == nil {
+			out.err = err
+		}
+		return
+	}
+	out.Write(encoded)
+}
+
+func formatFloat(value float64, bitSize int) (text string, quoted bool) {
+	switch {
+		case math.IsNaN(value):
+			return NaN, true
+		case math.IsInf(value, 1):
+			return PositiveInfinity, true
+		case math.IsInf(value, -1):
+			return NegativeInfinity, true
+		default:
+			return strconv.FormatFloat(value, 'g', -1, bitSize), false
+	}
+}
+
+func parseFloat(text string, bitSize int) (value float64, err error) {
+	switch text {
+		case NaN:
+			return math.NaN(), nil
+		case PositiveInfinity:
+			return math.Inf(1), nil
+		case NegativeInfinity:
+			return math.Inf(-1), nil
+		default:
+			return strconv.ParseFloat(text, bitSize)
+	}
+}
@@ -0,0 +1,545 @@
+package xdrjson
+
+import (
+	"io"
+	"bytes"
+	"strconv"
+	"encoding/json"
+	"encoding/binary"
+	"encoding/base64"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+func ToJSON(t *schema.Type, reader io.Reader, writer io.Writer, context *goxdr.DecodeContext) error {
+	state, err := NewJSONReadState(t, writer, context)
+	if err != nil {
+		return err
+	}
+	decoder := goxdr.NewStreamDecoder(reader, 0)
+	decoder.RejectTrailingData = true
+	return decoder.Decode(state)
+}
+
+func FromJSON(t *schema.Type, reader io.Reader, writer io.Writer) error {
+	return FromJSONLimited(t, reader, writer, goxdr.Limits{})
+}
+
+func FromJSONLimited(t *schema.Type, reader io.Reader, writer io.Writer, limits goxdr.Limits) (err error) {
+	decoder := json.NewDecoder(&limitedInput {
+		reader: reader,
+		limit: limits.MaxMessageBytes,
+	})
+	decoder.UseNumber()
+	source := &tokenSource {
+		decoder: decoder,
+		buffer: make([]byte, 8),
+		budget: &elementBudget {
+			limit: limits.MaxTotalElements,
+		},
+	}
+	err = source.encode(t, "", writer)
+	if err == nil {
+		err = checkTrailingJSON(decoder)
+	}
+	return
+}
+
+func checkTrailingJSON(decoder *json.Decoder) error {
+	decoder.More()
+	trailing, _ := io.ReadAll(decoder.Buffered())
+	trailing = bytes.TrimLeft(trailing, " \t\r\n")
+	if len(trailing) > 0 {
+		return goxdr.NewTrailingDataError(trailing)
+	}
+	_, err := decoder.Token()
+	switch err {
+		case io.EOF:
+			return nil
+		case nil:
+			trailing, _ = io.ReadAll(decoder.Buffered())
+			return goxdr.NewTrailingDataError(trailing)
+		default:
+			return err
+	}
+}
+
+type limitedInput struct {
+	reader io.Reader
+	limit uint64
+	count uint64
+}
+
+func(input *limitedInput) Read(bytes []byte) (readCount int, err error) {
+	if input.limit > 0 {
+		remaining := input.limit - input.count
+		if remaining == 0 {
+			var probe [1]byte
+			readCount, err = input.reader.Read(probe[:])
+			if readCount > 0 {
+				readCount = 0
+				err = &goxdr.LimitExceededError {
+					Limit: goxdr.LimitMessageBytes,
+					Maximum: input.limit,
+					Actual: input.limit + 1,
+				}
+			}
+			return
+		}
+		if uint64(len(bytes)) > remaining {
+			bytes = bytes[0:remaining]
+		}
+	}
+	readCount, err = input.reader.Read(bytes)
+	input.count += uint64(readCount)
+	return
+}
+
+type elementBudget struct {
+	limit uint64
+	count uint64
+}
+
+type tokenSource struct {
+	decoder *json.Decoder
+	pending json.Token
+	hasPending bool
+	buffer []byte
+	budget *elementBudget
+}
+
+func(source *tokenSource) chargeElement() error {
+	budget := source.budget
+	if budget == nil {
+		return nil
+	}
+	budget.count++
+	if budget.limit > 0 && budget.count > budget.limit {
+		return &goxdr.LimitExceededError {
+			Limit: goxdr.LimitTotalElements,
+			Maximum: budget.limit,
+			Actual: budget.count,
+		}
+	}
+	return nil
+}
+
+func(source *tokenSource) token() (token json.Token, err error) {
+	if source.hasPending {
+		source.hasPending = false
+		return source.pending, nil
+	}
+	return source.decoder.Token()
+}
+
+func(source *tokenSource) unread(token json.Token) {
+	source.pending = token
+	source.hasPending = true
+}
+
+func(source *tokenSource) more() bool {
+	return source.hasPending || source.decoder.More()
+}
+
+func(source *tokenSource) expectDelimiter(delimiter json.Delim, t *schema.Type, path string) error {
+	token, err := source.token()
+	if err != nil {
+		return err
+	}
+	if found, ok := token.(json.Delim); !ok || found != delimiter {
+		return valueError(path, t, token, "expected '" + delimiter.String() + "'")
+	}
+	return nil
+}
+
+func(source *tokenSource) key() (key string, done bool, err error) {
+	token, err := source.token()
+	if err != nil {
+		return
+	}
+	if delimiter, ok := token.(json.Delim); ok && delimiter == '}' {
+		done = true
+		return
+	}
+	key, _ = token.(string)
+	return
+}
+
+func(source *tokenSource) deferred(raw json.RawMessage) *tokenSource {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return &tokenSource {
+		decoder: decoder,
+		buffer: source.buffer,
+		budget: source.budget,
+	}
+}
+
+func valueError(path string, t *schema.Type, value any, reason string) error {
+	return &schema.ValueError {
+		Path: path,
+		Type: t,
+		Value: value,
+		Reason: reason,
+	}
+}
+
+func joinPath(parent string, child string) string {
+	switch {
+		case len(parent) == 0:
+			return child
+		case len(child) == 0:
+			return parent
+		default:
+			return parent + "." + child
+	}
+}
+
+func(source *tokenSource) encode(t *schema.Type, path string, writer io.Writer) (err error) {
+	resolved := t.Resolve()
+	if resolved == nil {
+		return &schema.UndefinedTypeError {
+			Name: t.Name,
+		}
+	}
+	switch resolved.Kind {
+		case schema.KindFixedArray, schema.KindVariableArray:
+			return source.encodeArray(resolved, path, writer)
+		case schema.KindStruct:
+			return source.encodeStruct(resolved, path, writer)
+		case schema.KindUnion:
+			return source.encodeUnion(resolved, path, writer)
+	}
+	token, err := source.token()
+	if err != nil {
+		return
+	}
+	switch resolved.Kind {
+		case schema.KindVoid:
+			if token != nil {
+				err = valueError(path, resolved, token, "expected null")
+			}
+		case schema.KindOptional:
+			if token == nil {
+				err = goxdr.WriteUint(0, source.buffer, writer)
+			} else {
+				source.unread(token)
+				err = goxdr.WriteUint(1, source.buffer, writer)
+				if err == nil {
+					err = source.encode(resolved.Element, path, writer)
+				}
+			}
+		case schema.KindFixedOpaque, schema.KindVariableOpaque:
+			text, ok := token.(string)
+			if !ok {
+				return valueError(path, resolved, token, "expected base64 string")
+			}
+			var data []byte
+			data, err = base64.StdEncoding.DecodeString(text)
+			if err != nil {
+				return valueError(path, resolved, token, err.Error())
+			}
+			err = writeValue(resolved, data, path, writer)
+		case schema.KindFloat, schema.KindDouble:
+			var value float64
+			switch typed := token.(type) {
+				case json.Number:
+					value, err = typed.Float64()
+				case string:
+					value, err = parseFloat(typed, 64)
+				default:
+					err = valueError(path, resolved, token, "expected number")
+			}
+			if err == nil {
+				err = writeValue(resolved, value, path, writer)
+			} else if _, isValueError := err.(*schema.ValueError); !isValueError {
+				err = valueError(path, resolved, token, err.Error())
+			}
+		default:
+			if _, isDelimiter := token.(json.Delim); isDelimiter {
+				return valueError(path, resolved, token, "unexpected '" + token.(json.Delim).String() + "'")
+			}
+			err = writeValue(resolved, token, path, writer)
+	}
+	return
+}
+
+func writeValue(t *schema.Type, value any, path string, writer io.Writer) error {
+	packet, err := schema.NewDynamicPacket(t, value)
+	if err != nil {
+		if valueErr, ok := err.(*schema.ValueError); ok && len(valueErr.Path) == 0 {
+			valueErr.Path = path
+		}
+		return err
+	}
+	return packet.WriteTo(nil, writer)
+}
+
+func(source *tokenSource) encodeArray(t *schema.Type, path string, writer io.Writer) (err error) {
+	err = source.expectDelimiter('[', t, path)
+	if err != nil {
+		return
+	}
+	if t.Kind == schema.KindVariableArray {
+		return source.encodeVariableArray(t, path, writer)
+	}
+	var count uint32
+	for source.more() {
+		if count >= t.Length {
+			return &goxdr.LengthMismatchError {
+				Subject: "fixed-length array " + path,
+				Expected: uint64(t.Length),
+				Actual: uint64(t.Length) + 1,
+			}
+		}
+		err = source.chargeElement()
+		if err == nil {
+			err = source.encode(t.Element, elementPath(path, count), writer)
+		}
+		if err != nil {
+			return
+		}
+		count++
+	}
+	err = source.expectDelimiter(']', t, path)
+	if err == nil && count != t.Length {
+		err = &goxdr.LengthMismatchError {
+			Subject: "fixed-length array " + path,
+			Expected: uint64(t.Length),
+			Actual: uint64(count),
+		}
+	}
+	return
+}
+
+type spillBuffer struct {
+	bytes []byte
+}
+
+func(spill *spillBuffer) Write(bytes []byte) (int, error) {
+	spill.bytes = append(spill.bytes, bytes...)
+	return len(bytes), nil
+}
+
+func(source *tokenSource) reserveCount(writer io.Writer) (target io.Writer, commit func(uint32) error, err error) {
+	if spill, ok := writer.(*spillBuffer); ok {
+		offset := len(spill.bytes)
+		spill.bytes = append(spill.bytes, 0, 0, 0, 0)
+		commit = func(count uint32) error {
+			binary.BigEndian.PutUint32(spill.bytes[offset:], count)
+			return nil
+		}
+		return writer, commit, nil
+	}
+	if seeker, ok := writer.(io.WriteSeeker); ok {
+		offset, seekErr := seeker.Seek(0, io.SeekCurrent)
+		if seekErr == nil {
+			commit = func(count uint32) error {
+				end, err := seeker.Seek(0, io.SeekCurrent)
+				if err == nil {
+					_, err = seeker.Seek(offset, io.SeekStart)
+				}
+				if err == nil {
+					err = goxdr.WriteUint(count, source.buffer, seeker)
+				}
+				if err == nil {
+					_, err = seeker.Seek(end, io.SeekStart)
+				}
+				return err
+			}
+			return writer, commit, goxdr.WriteUint(0, source.buffer, writer)
+		}
+	}
+	spill := &spillBuffer{}
+	commit = func(count uint32) error {
+		err := goxdr.WriteUint(count, source.buffer, writer)
+		if err == nil {
+			_, err = writer.Write(spill.bytes)
+		}
+		return err
+	}
+	return spill, commit, nil
+}
+
+func(source *tokenSource) encodeVariableArray(t *schema.Type, path string, writer io.Writer) (err error) {
+	target, commit, err := source.reserveCount(writer)
+	if err != nil {
+		return
+	}
+	var count uint32
+	for source.more() {
+		if count >= t.Length {
+			return &goxdr.MaxLengthError {
+				Subject: "Variable-length array " + path,
+				Maximum: t.Length,
+				Actual: t.Length + 1,
+			}
+		}
+		err = source.chargeElement()
+		if err == nil {
+			err = source.encode(t.Element, elementPath(path, count), target)
+		}
+		if err != nil {
+			return
+		}
+		count++
+	}
+	err = source.expectDelimiter(']', t, path)
+	if err == nil {
+		err = commit(count)
+	}
+	return
+}
+
+func elementPath(path string, index uint32) string {
+	return path + "[" + strconv.FormatUint(uint64(index), 10) + "]"
+}
+
+func(source *tokenSource) encodeStruct(t *schema.Type, path string, writer io.Writer) (err error) {
+	err = source.expectDelimiter('{', t, path)
+	if err != nil {
+		return
+	}
+	indices := make(map[string]int, len(t.Fields))
+	for index, field := range t.Fields {
+		indices[field.Name] = index
+	}
+	pending := make(map[int]json.RawMessage)
+	next := 0
+	flush := func() error {
+		for next < len(t.Fields) {
+			raw, buffered := pending[next]
+			if !buffered {
+				break
+			}
+			delete(pending, next)
+			field := t.Fields[next]
+			err := source.deferred(raw).encode(field.Type, joinPath(path, field.Name), writer)
+			if err != nil {
+				return err
+			}
+			next++
+		}
+		return nil
+	}
+	for {
+		key, done, err := source.key()
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+		index, known := indices[key]
+		_, buffered := pending[index]
+		if !known || index < next || buffered {
+			return valueError(joinPath(path, key), t, key, "unknown or duplicate struct field")
+		}
+		if index == next {
+			err = source.encode(t.Fields[index].Type, joinPath(path, key), writer)
+			next++
+		} else {
+			var raw json.RawMessage
+			err = source.decoder.Decode(&raw)
+			pending[index] = raw
+		}
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for next < len(t.Fields) {
+		field := t.Fields[next]
+		fieldType := field.Type.Resolve()
+		if fieldType == nil || (fieldType.Kind != schema.KindVoid && fieldType.Kind != schema.KindOptional) {
+			return valueError(joinPath(path, field.Name), field.Type, nil, "missing struct field")
+		}
+		if fieldType.Kind == schema.KindOptional {
+			err = goxdr.WriteUint(0, source.buffer, writer)
+			if err != nil {
+				return
+			}
+		}
+		next++
+		err = flush()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func(source *tokenSource) encodeUnion(t *schema.Type, path string, writer io.Writer) (err error) {
+	_, err = t.DiscriminantType()
+	if err != nil {
+		return
+	}
+	err = source.expectDelimiter('{', t, path)
+	if err != nil {
+		return
+	}
+	var arm *schema.Arm
+	var deferredValue json.RawMessage
+	var hasValue bool
+	encodeArm := func(armSource *tokenSource) error {
+		armPath := path
+		if len(arm.Name) > 0 {
+			armPath = joinPath(path, arm.Name)
+		}
+		return armSource.encode(arm.Type, armPath, writer)
+	}
+	for {
+		key, done, err := source.key()
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+		switch {
+			case key == schema.UnionTagKey && arm == nil:
+				var token json.Token
+				token, err = source.token()
+				if err != nil {
+					return err
+				}
+				var encoded bytes.Buffer
+				err = writeValue(t.Discriminant.Type, token, joinPath(path, key), &encoded)
+				if err != nil {
+					return err
+				}
+				arm = t.Arm(int32(binary.BigEndian.Uint32(encoded.Bytes())))
+				if arm == nil {
+					return valueError(joinPath(path, key), t, token, "no arm for discriminant")
+				}
+				_, err = encoded.WriteTo(writer)
+				if err == nil && hasValue {
+					err = encodeArm(source.deferred(deferredValue))
+				}
+			case key == schema.UnionValueKey && !hasValue:
+				hasValue = true
+				if arm == nil {
+					err = source.decoder.Decode(&deferredValue)
+				} else {
+					err = encodeArm(source)
+				}
+			default:
+				return valueError(joinPath(path, key), t, key, "unknown or duplicate union key")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if arm == nil {
+		return valueError(joinPath(path, schema.UnionTagKey), t, nil, "missing union tag")
+	}
+	if !hasValue {
+		armType := arm.Type.Resolve()
+		if armType == nil || armType.Kind != schema.KindVoid {
+			return valueError(joinPath(path, schema.UnionValueKey), arm.Type, nil, "missing union value")
+		}
+	}
+	return
+}
@@ -0,0 +1,143 @@
+package xdrjson
+
+import (
+	"os"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+func nestedArrayType() *schema.Type {
+	return schema.VariableArray(schema.Struct(
+		"entry",
+		schema.NewField("id", schema.UnsignedInt()),
+		schema.NewField("tags", schema.VariableArray(schema.String(16), 8)),
+	), 8)
+}
+
+func TestFromJSONVariableArray(t *testing.T) {
+	input := `[{"tags": ["a", "bc"], "id": 1}, {"id": 2, "tags": []}]`
+	var out bytes.Buffer
+	err := FromJSON(nestedArrayType(), strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packet, err := schema.NewDynamicPacket(nestedArrayType(), []any {
+		map[string]any{"id": 1, "tags": []any{"a", "bc"}},
+		map[string]any{"id": 2, "tags": []any{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected bytes.Buffer
+	err = packet.WriteTo(nil, &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), expected.Bytes()) {
+		t.Fatalf("FromJSON = %x, want %x", out.Bytes(), expected.Bytes())
+	}
+}
+
+func TestFromJSONVariableArrayTooLong(t *testing.T) {
+	input := `[1, 2, 3]`
+	var out bytes.Buffer
+	err := FromJSON(schema.VariableArray(schema.Int(), 2), strings.NewReader(input), &out)
+	if !errors.Is(err, goxdr.ErrMaxLengthExceeded) {
+		t.Fatalf("FromJSON = %v, want maximum length error", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("FromJSON wrote %d bytes for a rejected array", out.Len())
+	}
+}
+
+func TestFromJSONLimits(t *testing.T) {
+	arrayType := schema.VariableArray(schema.Int(), 100)
+	input := `[1, 2, 3, 4, 5, 6, 7, 8, 9, 10]`
+	var limitError *goxdr.LimitExceededError
+	err := FromJSONLimited(arrayType, strings.NewReader(input), &bytes.Buffer{}, goxdr.Limits {
+		MaxMessageBytes: 10,
+	})
+	if !errors.As(err, &limitError) || limitError.Limit != goxdr.LimitMessageBytes {
+		t.Fatalf("FromJSONLimited = %v, want message byte limit", err)
+	}
+	err = FromJSONLimited(arrayType, strings.NewReader(input), &bytes.Buffer{}, goxdr.Limits {
+		MaxTotalElements: 5,
+	})
+	if !errors.As(err, &limitError) || limitError.Limit != goxdr.LimitTotalElements {
+		t.Fatalf("FromJSONLimited = %v, want element limit", err)
+	}
+	err = FromJSONLimited(arrayType, strings.NewReader(input), &bytes.Buffer{}, goxdr.Limits{})
+	if err != nil {
+		t.Fatalf("FromJSONLimited without limits = %v", err)
+	}
+}
+
+func TestFromJSONSeekableOutput(t *testing.T) {
+	input := `[{"id": 1, "tags": ["a", "bc"]}, {"tags": ["def"], "id": 2}, {"id": 3, "tags": []}]`
+	var expected bytes.Buffer
+	err := FromJSON(nestedArrayType(), strings.NewReader(input), &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.CreateTemp(t.TempDir(), "xdr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	_, err = file.WriteString("head")
+	if err == nil {
+		err = FromJSON(nestedArrayType(), strings.NewReader(input), file)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, append([]byte("head"), expected.Bytes()...)) {
+		t.Fatalf("FromJSON to file = %x, want head followed by %x", written, expected.Bytes())
+	}
+}
+
+func TestFromJSONIsUnlimited(t *testing.T) {
+	count := goxdr.DefaultLimits.MaxTotalElements + 1
+	input := "[" + strings.Repeat("null,", int(count - 1)) + "null]"
+	var out bytes.Buffer
+	err := FromJSON(schema.VariableArray(schema.Void(), uint32(count)), strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("FromJSON = %v, want no default limits", err)
+	}
+	if out.Len() != 4 {
+		t.Fatalf("FromJSON wrote %d bytes, want only the count", out.Len())
+	}
+}
+
+func TestFromJSONTrailingData(t *testing.T) {
+	for input, preview := range map[string]string {
+		"1 2": "2",
+		"[1]]": "]",
+		"1 x": "x",
+	} {
+		valueType := schema.Int()
+		if strings.HasPrefix(input, "[") {
+			valueType = schema.VariableArray(schema.Int(), 4)
+		}
+		err := FromJSON(valueType, strings.NewReader(input), &bytes.Buffer{})
+		var trailingError *goxdr.TrailingDataError
+		if !errors.Is(err, goxdr.ErrTrailingData) || !errors.As(err, &trailingError) {
+			t.Fatalf("%q: FromJSON = %v, want trailing data error", input, err)
+		}
+		if !strings.HasPrefix(string(trailingError.Preview), preview) {
+			t.Fatalf("%q: Preview = %q, want it to start with %q", input, trailingError.Preview, preview)
+		}
+	}
+	err := FromJSON(schema.Int(), strings.NewReader("1 \n"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("FromJSON with trailing whitespace = %v", err)
+	}
+}
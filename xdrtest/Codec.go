@@ -0,0 +1,62 @@
+package xdrtest
+
+import (
+	"bytes"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+type Codec interface {
+	Name() string
+	Encode(any) ([]byte, error)
+	NewReadState() goxdr.ReadState
+	Value(goxdr.ReadState) any
+}
+
+type SchemaCodec struct {
+	Type *schema.Type
+	DecodeContext func() *goxdr.DecodeContext
+}
+
+func NewSchemaCodec(t *schema.Type) *SchemaCodec {
+	return &SchemaCodec {
+		Type: t,
+	}
+}
+
+func(codec *SchemaCodec) Name() string {
+	return codec.Type.DisplayName()
+}
+
+func(codec *SchemaCodec) Encode(value any) (encoded []byte, err error) {
+	packet, err := schema.NewDynamicPacket(codec.Type, value)
+	if err != nil {
+		return
+	}
+	var buffer bytes.Buffer
+	err = packet.WriteTo(make([]byte, 8), &buffer)
+	if err == nil && uint64(buffer.Len()) != uint64(packet.ByteSize()) {
+		err = &goxdr.LengthMismatchError {
+			Subject: "encoded packet",
+			Expected: uint64(packet.ByteSize()),
+			Actual: uint64(buffer.Len()),
+		}
+	}
+	encoded = buffer.Bytes()
+	return
+}
+
+func(codec *SchemaCodec) NewReadState() goxdr.ReadState {
+	var context *goxdr.DecodeContext
+	if codec.DecodeContext != nil {
+		context = codec.DecodeContext()
+	}
+	state, _ := schema.NewDynamicReadState(codec.Type, context)
+	return state
+}
+
+func(codec *SchemaCodec) Value(state goxdr.ReadState) any {
+	return state.(*schema.DynamicReadState).Value()
+}
+
+var _ Codec = &SchemaCodec{}
@@ -0,0 +1,237 @@
+package xdrtest
+
+import (
+	"math"
+	"math/rand"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+const defaultMaxArrayLength = 8
+const defaultMaxOpaqueLength = 37
+const defaultMaxDepth = 6
+
+type Generator struct {
+	Rand *rand.Rand
+	MaxArrayLength uint32
+	MaxOpaqueLength uint32
+	MaxDepth int
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator {
+		Rand: rand.New(rand.NewSource(seed)),
+		MaxArrayLength: defaultMaxArrayLength,
+		MaxOpaqueLength: defaultMaxOpaqueLength,
+		MaxDepth: defaultMaxDepth,
+	}
+}
+
+func(generator *Generator) Value(t *schema.Type) any {
+	return generator.value(t, 0)
+}
+
+func(generator *Generator) length(maximum uint32, limit uint32) uint32 {
+	if maximum > limit {
+		maximum = limit
+	}
+	return uint32(generator.Rand.Int63n(int64(maximum) + 1))
+}
+
+func(generator *Generator) bits32() uint32 {
+	switch generator.Rand.Intn(8) {
+		case 0:
+			return 0
+		case 1:
+			return math.MaxUint32
+		case 2:
+			return math.MaxInt32
+		case 3:
+			return 1 << 31
+		default:
+			return generator.Rand.Uint32()
+	}
+}
+
+func(generator *Generator) bits64() uint64 {
+	switch generator.Rand.Intn(8) {
+		case 0:
+			return 0
+		case 1:
+			return math.MaxUint64
+		case 2:
+			return math.MaxInt64
+		case 3:
+			return 1 << 63
+		default:
+			return generator.Rand.Uint64()
+	}
+}
+
+func(generator *Generator) float32() float32 {
+	switch generator.Rand.Intn(10) {
+		case 0:
+			return float32(math.NaN())
+		case 1:
+			return float32(math.Inf(1))
+		case 2:
+			return float32(math.Inf(-1))
+		case 3:
+			return math.Float32frombits(1)
+		case 4:
+			return math.Float32frombits(1 << 31)
+		default:
+			return math.Float32frombits(generator.Rand.Uint32())
+	}
+}
+
+func(generator *Generator) float64() float64 {
+	switch generator.Rand.Intn(10) {
+		case 0:
+			return math.NaN()
+		case 1:
+			return math.Inf(1)
+		case 2:
+			return math.Inf(-1)
+		case 3:
+			return math.Float64frombits(1)
+		case 4:
+			return math.Float64frombits(1 << 63)
+		default:
+			return math.Float64frombits(generator.Rand.Uint64())
+	}
+}
+
+func(generator *Generator) bytes(length uint32) []byte {
+	bytes := make([]byte, length)
+	generator.Rand.Read(bytes)
+	return bytes
+}
+
+func(generator *Generator) value(t *schema.Type, depth int) any {
+	t = t.Resolve()
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+		case schema.KindInt:
+			return int32(generator.bits32())
+		case schema.KindUnsignedInt:
+			return generator.bits32()
+		case schema.KindHyper:
+			return int64(generator.bits64())
+		case schema.KindUnsignedHyper:
+			return generator.bits64()
+		case schema.KindFloat:
+			return generator.float32()
+		case schema.KindDouble:
+			return generator.float64()
+		case schema.KindBool:
+			return generator.Rand.Intn(2) == 1
+		case schema.KindEnum:
+			if len(t.Values) == 0 {
+				return int32(0)
+			}
+			return t.Values[generator.Rand.Intn(len(t.Values))].Value
+		case schema.KindFixedOpaque:
+			return generator.bytes(t.Length)
+		case schema.KindVariableOpaque:
+			return generator.bytes(generator.length(t.Length, generator.MaxOpaqueLength))
+		case schema.KindString:
+			return string(generator.bytes(generator.length(t.Length, generator.MaxOpaqueLength)))
+		case schema.KindFixedArray, schema.KindVariableArray:
+			length := t.Length
+			if t.Kind == schema.KindVariableArray {
+				maximum := generator.MaxArrayLength
+				if depth >= generator.MaxDepth {
+					maximum = 0
+				}
+				length = generator.length(t.Length, maximum)
+			}
+			elements := make([]any, length)
+			for index := range elements {
+				elements[index] = generator.value(t.Element, depth + 1)
+			}
+			return elements
+		case schema.KindOptional:
+			if depth >= generator.MaxDepth || generator.Rand.Intn(3) == 0 {
+				return nil
+			}
+			return generator.value(t.Element, depth + 1)
+		case schema.KindStruct:
+			fields := make(map[string]any, len(t.Fields))
+			for _, field := range t.Fields {
+				fields[field.Name] = generator.value(field.Type, depth + 1)
+			}
+			return fields
+		case schema.KindUnion:
+			return generator.union(t, depth)
+		default:
+			return nil
+	}
+}
+
+func(generator *Generator) union(t *schema.Type, depth int) any {
+	var candidates []int32
+	for _, arm := range t.Arms {
+		candidates = append(candidates, arm.Cases...)
+	}
+	if t.Default != nil {
+		if discriminant, found := generator.defaultDiscriminant(t); found {
+			candidates = append(candidates, discriminant)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	discriminant := candidates[generator.Rand.Intn(len(candidates))]
+	var tag any
+	switch t.Discriminant.Type.Resolve().Kind {
+		case schema.KindUnsignedInt:
+			tag = uint32(discriminant)
+		case schema.KindBool:
+			tag = discriminant != 0
+		default:
+			tag = discriminant
+	}
+	return map[string]any {
+		schema.UnionTagKey: tag,
+		schema.UnionValueKey: generator.value(t.Arm(discriminant).Type, depth + 1),
+	}
+}
+
+func(generator *Generator) defaultDiscriminant(t *schema.Type) (discriminant int32, found bool) {
+	var pool []int32
+	discriminantType := t.Discriminant.Type.Resolve()
+	switch discriminantType.Kind {
+		case schema.KindBool:
+			pool = []int32{0, 1}
+		case schema.KindEnum:
+			for _, value := range discriminantType.Values {
+				pool = append(pool, value.Value)
+			}
+		default:
+			for attempt := 0; attempt < 16; attempt++ {
+				pool = append(pool, int32(generator.bits32()))
+			}
+	}
+	generator.Rand.Shuffle(len(pool), func(i int, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	for _, candidate := range pool {
+		if !explicitCase(t, candidate) {
+			return candidate, true
+		}
+	}
+	return
+}
+
+func explicitCase(t *schema.Type, discriminant int32) bool {
+	for _, arm := range t.Arms {
+		for _, value := range arm.Cases {
+			if value == discriminant {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package xdrtest
+
+import (
+	"strconv"
+	"testing"
+	"github.com/UncleSniper/goxdr"
+)
+
+var benchmarkChunkSizes = []int{1, 3, 4, 64, 4096}
+
+func benchmarkPayload(size int) []byte {
+	payload := make([]byte, size)
+	for index := range payload {
+		payload[index] = byte(index * 31)
+	}
+	return payload
+}
+
+func feedChunks(b *testing.B, state goxdr.ReadState, data []byte, chunkSize int) {
+	for offset := 0; offset < len(data); {
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		readCount, isFull := state.Update(data[offset:end])
+		offset += readCount
+		if isFull {
+			break
+		}
+	}
+	err := state.EndPacket()
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func runChunked(b *testing.B, data []byte, newState func() goxdr.ReadState) {
+	for _, chunkSize := range benchmarkChunkSizes {
+		b.Run("chunk=" + strconv.Itoa(chunkSize), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for iteration := 0; iteration < b.N; iteration++ {
+				feedChunks(b, newState(), data, chunkSize)
+			}
+		})
+	}
+}
+
+func BenchmarkPrimitiveReadState(b *testing.B) {
+	for _, size := range []int{4, 8} {
+		data := benchmarkPayload(size)
+		b.Run("size=" + strconv.Itoa(size), func(b *testing.B) {
+			state, _ := goxdr.NewPrimitiveReadState(size)
+			runChunked(b, data, func() goxdr.ReadState {
+				state.Reset(size)
+				return state
+			})
+		})
+	}
+}
+
+type discardHandler struct {}
+
+func(handler discardHandler) Update(bytes []byte) (int, bool) {
+	return len(bytes), false
+}
+
+func(handler discardHandler) EndPacket() error {
+	return nil
+}
+
+func BenchmarkFixedLengthOpaqueReadState(b *testing.B) {
+	for _, size := range []int{13, 1024, 65536} {
+		data := benchmarkPayload((size + 3) &^ 3)
+		for index := size; index < len(data); index++ {
+			data[index] = 0
+		}
+		b.Run("size=" + strconv.Itoa(size), func(b *testing.B) {
+			runChunked(b, data, func() goxdr.ReadState {
+				return &goxdr.FixedLengthOpaqueReadState {
+					ExpectedLength: uint32(size),
+					Handler: discardHandler{},
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkVariableLengthArrayReadState(b *testing.B) {
+	for _, count := range []int{16, 4096} {
+		data := benchmarkPayload(4 + 4 * count)
+		data[0], data[1], data[2], data[3] = byte(count >> 24), byte(count >> 16), byte(count >> 8), byte(count)
+		b.Run("count=" + strconv.Itoa(count), func(b *testing.B) {
+			elements := make([]goxdr.PrimitiveReadState, count)
+			runChunked(b, data, func() goxdr.ReadState {
+				primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+				return &goxdr.VariableLengthArrayReadState[uint32] {
+					PrimitiveState: primitiveState,
+					FixedLengthState: &goxdr.FixedLengthArrayReadState[uint32] {
+						HandlerFactory: func(index uint32, size uint32) (goxdr.TypedReadState[uint32], error) {
+							element := &elements[index]
+							element.Reset(4)
+							return element, nil
+						},
+					},
+					MaxLength: uint32(count),
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkDynamicReadState(b *testing.B) {
+	codec := NewSchemaCodec(rfcFileType())
+	runChunked(b, RFCFileExample(), codec.NewReadState)
+}
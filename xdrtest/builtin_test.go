@@ -0,0 +1,334 @@
+package xdrtest
+
+import (
+	"io"
+	"bytes"
+	"testing"
+	"math/rand"
+	"github.com/UncleSniper/goxdr"
+)
+
+type stateCodec struct {
+	name string
+	generate func(*rand.Rand) any
+	encode func(any, []byte, io.Writer) error
+	newState func() goxdr.ReadState
+	value func(goxdr.ReadState) any
+}
+
+func(codec *stateCodec) Name() string {
+	return codec.name
+}
+
+func(codec *stateCodec) Encode(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := codec.encode(value, make([]byte, 8), &buffer)
+	return buffer.Bytes(), err
+}
+
+func(codec *stateCodec) NewReadState() goxdr.ReadState {
+	return codec.newState()
+}
+
+func(codec *stateCodec) Value(state goxdr.ReadState) any {
+	return codec.value(state)
+}
+
+func randomBytes(random *rand.Rand, length int) []byte {
+	data := make([]byte, length)
+	random.Read(data)
+	return data
+}
+
+func randomElements(random *rand.Rand, length int) []any {
+	elements := make([]any, length)
+	for index := range elements {
+		elements[index] = random.Uint32()
+	}
+	return elements
+}
+
+func writeElement(element any, buffer []byte, writer io.Writer) error {
+	return goxdr.WriteUint(element.(uint32), buffer, writer)
+}
+
+func newPrimitive(size int) goxdr.ReadState {
+	state, _ := goxdr.NewPrimitiveReadState(size)
+	return state
+}
+
+func opaqueBytes(handler goxdr.ReadState) any {
+	return append([]byte{}, handler.(*goxdr.WriterReadState).Writer.(*bytes.Buffer).Bytes()...)
+}
+
+func newOpaqueHandler() *goxdr.WriterReadState {
+	return goxdr.NewWriterReadState(&bytes.Buffer{})
+}
+
+type collectedArray struct {
+	goxdr.ReadState
+	values []any
+}
+
+func(array *collectedArray) done(index uint32, element goxdr.TypedReadState[uint32]) error {
+	array.values = append(array.values, element.(*goxdr.PrimitiveReadState).AsUint())
+	return nil
+}
+
+func elementFactory(uint32, uint32) (goxdr.TypedReadState[uint32], error) {
+	return goxdr.NewPrimitiveReadState(4)
+}
+
+func newFixedArray(length uint32) (*collectedArray, *goxdr.FixedLengthArrayReadState[uint32]) {
+	array := &collectedArray{}
+	fixed := &goxdr.FixedLengthArrayReadState[uint32] {
+		ExpectedLength: length,
+		HandlerFactory: elementFactory,
+		ElementDone: array.done,
+	}
+	array.ReadState = fixed
+	return array, fixed
+}
+
+func collectedValues(state goxdr.ReadState) any {
+	values := state.(*collectedArray).values
+	if values == nil {
+		values = []any{}
+	}
+	return values
+}
+
+type collectedUnion struct {
+	goxdr.ReadState
+	discriminant func() any
+	arm goxdr.ReadState
+}
+
+func(union *collectedUnion) capture(factory goxdr.TypedReadStateFactory[any]) goxdr.TypedReadStateFactory[any] {
+	return func(discriminant uint32, size uint32) (goxdr.TypedReadState[any], error) {
+		arm, err := factory(discriminant, size)
+		union.arm = arm
+		return arm, err
+	}
+}
+
+func unionValue(state goxdr.ReadState) any {
+	union := state.(*collectedUnion)
+	var armValue any
+	if primitive, ok := union.arm.(*goxdr.PrimitiveReadState); ok {
+		armValue = primitive.AsHyperUint()
+	}
+	return map[string]any {
+		"tag": union.discriminant(),
+		"value": armValue,
+	}
+}
+
+func hyperArm(uint32, uint32) (goxdr.TypedReadState[any], error) {
+	return goxdr.NewPrimitiveReadState(8)
+}
+
+func armPacket(value any) goxdr.Packet {
+	if value == nil {
+		return nil
+	}
+	var buffer bytes.Buffer
+	goxdr.WriteHyperUint(value.(uint64), make([]byte, 8), &buffer)
+	return goxdr.ByteSlicePacket {
+		Bytes: buffer.Bytes(),
+	}
+}
+
+func builtinCodecs() []*stateCodec {
+	return []*stateCodec {
+		{
+			name: "PrimitiveReadState(4)",
+			generate: func(random *rand.Rand) any {
+				return random.Uint32()
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteUint(value.(uint32), buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				return newPrimitive(4)
+			},
+			value: func(state goxdr.ReadState) any {
+				return state.(*goxdr.PrimitiveReadState).AsUint()
+			},
+		},
+		{
+			name: "PrimitiveReadState(8)",
+			generate: func(random *rand.Rand) any {
+				return random.Uint64()
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteHyperUint(value.(uint64), buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				return newPrimitive(8)
+			},
+			value: func(state goxdr.ReadState) any {
+				return state.(*goxdr.PrimitiveReadState).AsHyperUint()
+			},
+		},
+		{
+			name: "FixedLengthOpaqueReadState",
+			generate: func(random *rand.Rand) any {
+				return randomBytes(random, 7)
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteFixedLengthOpaquePacket(goxdr.ByteSlicePacket {
+					Bytes: value.([]byte),
+				}, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				return &goxdr.FixedLengthOpaqueReadState {
+					ExpectedLength: 7,
+					Handler: newOpaqueHandler(),
+					PaddingPolicy: goxdr.PaddingStrict,
+				}
+			},
+			value: func(state goxdr.ReadState) any {
+				return opaqueBytes(state.(*goxdr.FixedLengthOpaqueReadState).Handler)
+			},
+		},
+		{
+			name: "VariableLengthOpaqueReadState",
+			generate: func(random *rand.Rand) any {
+				return randomBytes(random, random.Intn(41))
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteVariableLengthOpaquePacket(goxdr.ByteSlicePacket {
+					Bytes: value.([]byte),
+				}, 40, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+				return &goxdr.VariableLengthOpaqueReadState {
+					PrimitiveState: primitiveState,
+					FixedLengthState: &goxdr.FixedLengthOpaqueReadState {
+						Handler: newOpaqueHandler(),
+						PaddingPolicy: goxdr.PaddingStrict,
+					},
+					MaxLength: 40,
+				}
+			},
+			value: func(state goxdr.ReadState) any {
+				return opaqueBytes(state.(*goxdr.VariableLengthOpaqueReadState).FixedLengthState.Handler)
+			},
+		},
+		{
+			name: "FixedLengthArrayReadState",
+			generate: func(random *rand.Rand) any {
+				return randomElements(random, 5)
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteArray(value.([]any), 5, writeElement, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				array, _ := newFixedArray(5)
+				return array
+			},
+			value: collectedValues,
+		},
+		{
+			name: "VariableLengthArrayReadState",
+			generate: func(random *rand.Rand) any {
+				return randomElements(random, random.Intn(7))
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				return goxdr.WriteVarArray(value.([]any), 6, writeElement, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				array, fixed := newFixedArray(0)
+				primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+				array.ReadState = &goxdr.VariableLengthArrayReadState[uint32] {
+					PrimitiveState: primitiveState,
+					FixedLengthState: fixed,
+					MaxLength: 6,
+				}
+				return array
+			},
+			value: collectedValues,
+		},
+		{
+			name: "TaggedUnionReadState",
+			generate: func(random *rand.Rand) any {
+				discriminant := uint32(random.Intn(3))
+				var value any
+				if discriminant > 0 {
+					value = random.Uint64()
+				}
+				return map[string]any {
+					"tag": discriminant,
+					"value": value,
+				}
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				union := value.(map[string]any)
+				return goxdr.WriteUnion(union["tag"].(uint32), armPacket(union["value"]), goxdr.AnyCase, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				union := &collectedUnion{}
+				primitiveState, _ := goxdr.NewPrimitiveReadState(4)
+				tagged := &goxdr.TaggedUnionReadState[any] {
+					PrimitiveState: primitiveState,
+					Arms: map[uint32]goxdr.TypedReadStateFactory[any] {
+						0: union.capture(goxdr.VoidArm[any]),
+						1: union.capture(hyperArm),
+					},
+					DefaultArm: union.capture(hyperArm),
+				}
+				union.ReadState = tagged
+				union.discriminant = func() any {
+					return tagged.PrimitiveState.AsUint()
+				}
+				return union
+			},
+			value: unionValue,
+		},
+		{
+			name: "TypedUnionReadState",
+			generate: func(random *rand.Rand) any {
+				if random.Intn(2) == 0 {
+					return map[string]any {
+						"tag": int32(-1),
+						"value": nil,
+					}
+				}
+				return map[string]any {
+					"tag": int32(1),
+					"value": random.Uint64(),
+				}
+			},
+			encode: func(value any, buffer []byte, writer io.Writer) error {
+				union := value.(map[string]any)
+				return goxdr.WriteUnion(uint32(union["tag"].(int32)), armPacket(union["value"]), goxdr.AnyCase, buffer, writer)
+			},
+			newState: func() goxdr.ReadState {
+				union := &collectedUnion{}
+				typed := goxdr.NewTypedUnionReadState[any, int32]("typed", nil)
+				typed.SetArm(-1, union.capture(goxdr.VoidArm[any]))
+				typed.SetArm(1, union.capture(hyperArm))
+				union.ReadState = typed
+				union.discriminant = func() any {
+					return typed.Discriminant()
+				}
+				return union
+			},
+			value: unionValue,
+		},
+	}
+}
+
+func TestBuiltinReadStates(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	for _, codec := range builtinCodecs() {
+		for iteration := 0; iteration < 50; iteration++ {
+			err := RoundTrip(codec, codec.generate(random), random)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
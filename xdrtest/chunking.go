@@ -0,0 +1,68 @@
+package xdrtest
+
+import (
+	"strconv"
+	"math/rand"
+)
+
+type Chunking struct {
+	Name string
+	Chunks [][]byte
+}
+
+func WholeChunking(data []byte) Chunking {
+	return Chunking {
+		Name: "all at once",
+		Chunks: [][]byte{data},
+	}
+}
+
+func ByteChunking(data []byte) Chunking {
+	chunks := make([][]byte, len(data))
+	for index := range data {
+		chunks[index] = data[index:index + 1]
+	}
+	return Chunking {
+		Name: "byte by byte",
+		Chunks: chunks,
+	}
+}
+
+func SplitChunking(data []byte, at int) Chunking {
+	return Chunking {
+		Name: "split at " + strconv.Itoa(at),
+		Chunks: [][]byte{data[0:at], data[at:]},
+	}
+}
+
+func RandomChunking(data []byte, random *rand.Rand) Chunking {
+	var chunks [][]byte
+	var sizes []byte
+	for offset := 0; offset < len(data); {
+		size := 1 + random.Intn(9)
+		if size > len(data) - offset {
+			size = len(data) - offset
+		}
+		chunks = append(chunks, data[offset:offset + size])
+		sizes = strconv.AppendInt(append(sizes, ' '), int64(size), 10)
+		offset += size
+	}
+	return Chunking {
+		Name: "random chunks" + string(sizes),
+		Chunks: chunks,
+	}
+}
+
+func Chunkings(data []byte, random *rand.Rand, randomCount int) []Chunking {
+	chunkings := []Chunking {
+		WholeChunking(data),
+		ByteChunking(data),
+	}
+	for at := 1; at < len(data); at++ {
+		chunkings = append(chunkings, SplitChunking(data, at))
+	}
+	for count := 0; count < randomCount; count++ {
+		chunkings = append(chunkings, RandomChunking(data, random))
+	}
+	return chunkings
+}
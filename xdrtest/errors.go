@@ -0,0 +1,68 @@
+package xdrtest
+
+import (
+	"fmt"
+	"strings"
+	"encoding/hex"
+)
+
+type StallError struct {
+	Offset int
+	Offered int
+}
+
+func(err *StallError) Error() string {
+	return fmt.Sprintf("Read state consumed nothing from %d offered bytes at offset %d without becoming full", err.Offered, err.Offset)
+}
+
+type MismatchError struct {
+	Expected any
+	Actual any
+}
+
+func(err *MismatchError) Error() string {
+	return fmt.Sprintf("Decoded value %#v does not match original value %#v", err.Actual, err.Expected)
+}
+
+type RoundTripError struct {
+	Codec string
+	Chunking string
+	Value any
+	Encoding []byte
+	Cause error
+}
+
+func(err *RoundTripError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Round trip of ")
+	builder.WriteString(err.Codec)
+	if len(err.Chunking) > 0 {
+		builder.WriteString(" (")
+		builder.WriteString(err.Chunking)
+		builder.WriteString(")")
+	}
+	builder.WriteString(" failed: ")
+	builder.WriteString(err.Cause.Error())
+	if err.Encoding != nil {
+		builder.WriteString("; encoding: ")
+		builder.WriteString(hex.EncodeToString(err.Encoding))
+	}
+	return builder.String()
+}
+
+func(err *RoundTripError) Unwrap() error {
+	return err.Cause
+}
+
+type ChunkingError struct {
+	Chunking string
+	Cause error
+}
+
+func(err *ChunkingError) Error() string {
+	return err.Chunking + ": " + err.Cause.Error()
+}
+
+func(err *ChunkingError) Unwrap() error {
+	return err.Cause
+}
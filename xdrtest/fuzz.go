@@ -0,0 +1,111 @@
+package xdrtest
+
+import (
+	"github.com/UncleSniper/goxdr"
+)
+
+type outcome struct {
+	consumed int
+	full bool
+	err string
+	value any
+}
+
+func sameOutcome(expected outcome, actual outcome) bool {
+	if expected.err != actual.err {
+		return false
+	}
+	if len(expected.err) > 0 {
+		return true
+	}
+	return expected.consumed == actual.consumed && expected.full == actual.full && Equal(expected.value, actual.value)
+}
+
+func fuzzChunkings(data []byte, split uint8) []Chunking {
+	size := int(split % 13) + 1
+	var chunks [][]byte
+	for offset := 0; offset < len(data); offset += size {
+		end := offset + size
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, data[offset:end])
+	}
+	chunkings := []Chunking {
+		WholeChunking(data),
+		ByteChunking(data),
+		Chunking {
+			Name: "fixed chunks",
+			Chunks: chunks,
+		},
+	}
+	if len(data) > 0 {
+		chunkings = append(chunkings, SplitChunking(data, int(split) % len(data)))
+	}
+	return chunkings
+}
+
+func consume(state goxdr.ReadState, chunks [][]byte) (result outcome, err error) {
+	for _, chunk := range chunks {
+		for len(chunk) > 0 && !result.full {
+			var readCount int
+			readCount, result.full = state.Update(chunk)
+			if readCount < 0 || readCount > len(chunk) {
+				err = &goxdr.OverreadError {
+					Subject: "Read state under test",
+					ReadCount: readCount,
+					Offered: len(chunk),
+				}
+				return
+			}
+			if readCount == 0 && !result.full {
+				err = &StallError {
+					Offset: result.consumed,
+					Offered: len(chunk),
+				}
+				return
+			}
+			result.consumed += readCount
+			chunk = chunk[readCount:]
+		}
+	}
+	endErr := state.EndPacket()
+	if endErr != nil {
+		result.err = endErr.Error()
+	}
+	return
+}
+
+func CheckChunkingInvariants(
+	data []byte,
+	split uint8,
+	newState func() goxdr.ReadState,
+	value func(goxdr.ReadState) any,
+) error {
+	var reference outcome
+	for index, chunking := range fuzzChunkings(data, split) {
+		state := newState()
+		result, err := consume(state, chunking.Chunks)
+		if err != nil {
+			return &ChunkingError {
+				Chunking: chunking.Name,
+				Cause: err,
+			}
+		}
+		if len(result.err) == 0 && value != nil {
+			result.value = value(state)
+		}
+		if index == 0 {
+			reference = result
+		} else if !sameOutcome(reference, result) {
+			return &ChunkingError {
+				Chunking: chunking.Name,
+				Cause: &MismatchError {
+					Expected: reference,
+					Actual: result,
+				},
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package xdrtest
+
+import (
+	"github.com/UncleSniper/goxdr/schema"
+	"github.com/UncleSniper/goxdr/vectors"
+)
+
+const RFCFileSchema = vectors.RFCFileSchema
+
+func RFCFileExample() []byte {
+	return vectors.RFCFileExample()
+}
+
+func RFCFileExampleValue() map[string]any {
+	return vectors.RFCFileExampleValue()
+}
+
+func rfcFileType() *schema.Type {
+	definitions, err := schema.Parse(RFCFileSchema)
+	if err != nil {
+		panic(err)
+	}
+	file, err := definitions.Lookup("file")
+	if err != nil {
+		panic(err)
+	}
+	return file
+}
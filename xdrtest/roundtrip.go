@@ -0,0 +1,148 @@
+package xdrtest
+
+import (
+	"math"
+	"bytes"
+	"math/rand"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+)
+
+const defaultRandomChunkings = 4
+
+func Feed(state goxdr.ReadState, chunks [][]byte) (err error) {
+	var offset int
+	var isFull bool
+	for chunkIndex, chunk := range chunks {
+		for len(chunk) > 0 {
+			if isFull {
+				trailing := append([]byte{}, chunk...)
+				for _, rest := range chunks[chunkIndex + 1:] {
+					trailing = append(trailing, rest...)
+				}
+				err = state.EndPacket()
+				if err == nil {
+					err = goxdr.NewTrailingDataError(trailing)
+				}
+				return
+			}
+			var readCount int
+			readCount, isFull = state.Update(chunk)
+			if readCount < 0 || readCount > len(chunk) {
+				return &goxdr.OverreadError {
+					Subject: "Root read state",
+					ReadCount: readCount,
+					Offered: len(chunk),
+				}
+			}
+			if readCount == 0 && !isFull {
+				return &StallError {
+					Offset: offset,
+					Offered: len(chunk),
+				}
+			}
+			offset += readCount
+			chunk = chunk[readCount:]
+		}
+	}
+	return state.EndPacket()
+}
+
+func Equal(expected any, actual any) bool {
+	switch typed := expected.(type) {
+		case float32:
+			other, ok := actual.(float32)
+			return ok && math.Float32bits(typed) == math.Float32bits(other)
+		case float64:
+			other, ok := actual.(float64)
+			return ok && math.Float64bits(typed) == math.Float64bits(other)
+		case []byte:
+			other, ok := actual.([]byte)
+			return ok && bytes.Equal(typed, other)
+		case []any:
+			other, ok := actual.([]any)
+			if !ok || len(typed) != len(other) {
+				return false
+			}
+			for index := range typed {
+				if !Equal(typed[index], other[index]) {
+					return false
+				}
+			}
+			return true
+		case map[string]any:
+			other, ok := actual.(map[string]any)
+			if !ok || len(typed) != len(other) {
+				return false
+			}
+			for key, value := range typed {
+				otherValue, present := other[key]
+				if !present || !Equal(value, otherValue) {
+					return false
+				}
+			}
+			return true
+		default:
+			return expected == actual
+	}
+}
+
+func RoundTrip(codec Codec, value any, random *rand.Rand) error {
+	encoded, err := codec.Encode(value)
+	if err != nil {
+		return &RoundTripError {
+			Codec: codec.Name(),
+			Value: value,
+			Cause: err,
+		}
+	}
+	for _, chunking := range Chunkings(encoded, random, defaultRandomChunkings) {
+		state := codec.NewReadState()
+		err = Feed(state, chunking.Chunks)
+		if err == nil {
+			decoded := codec.Value(state)
+			if !Equal(value, decoded) {
+				err = &MismatchError {
+					Expected: value,
+					Actual: decoded,
+				}
+			}
+		}
+		if err != nil {
+			return &RoundTripError {
+				Codec: codec.Name(),
+				Chunking: chunking.Name,
+				Value: value,
+				Encoding: encoded,
+				Cause: err,
+			}
+		}
+	}
+	return nil
+}
+
+func Check(t *schema.Type, iterations int, seed int64) error {
+	generator := NewGenerator(seed)
+	codec := NewSchemaCodec(t)
+	for iteration := 0; iteration < iterations; iteration++ {
+		err := RoundTrip(codec, generator.Value(t), generator.Rand)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CheckSchema(definitions *schema.Schema, iterations int, seed int64) error {
+	for _, name := range definitions.Names() {
+		t, err := definitions.Lookup(name)
+		if err != nil {
+			return err
+		}
+		err = Check(t, iterations, seed)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package xdrtest
+
+import (
+	"errors"
+	"testing"
+	"math/rand"
+	"github.com/UncleSniper/goxdr"
+	"github.com/UncleSniper/goxdr/schema"
+	"github.com/UncleSniper/goxdr/vectors"
+)
+
+func TestVectors(t *testing.T) {
+	err := vectors.VerifyAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := rand.New(rand.NewSource(1))
+	for _, vector := range vectors.Vectors() {
+		err = RoundTrip(NewSchemaCodec(vector.Type), vector.Value, random)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.Name, err)
+		}
+		err = Check(vector.Type, 25, int64(len(vector.Encoding)))
+		if err != nil {
+			t.Fatalf("%s: %v", vector.Name, err)
+		}
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	definitions, err := schema.Parse(RFCFileSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = CheckSchema(definitions, 50, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFeedTrailingData(t *testing.T) {
+	state, _ := goxdr.NewPrimitiveReadState(4)
+	err := Feed(state, [][]byte{{0, 0}, {0, 1, 0xAB}, {0xCD}})
+	var trailingError *goxdr.TrailingDataError
+	if !errors.As(err, &trailingError) || trailingError.Count != 2 {
+		t.Fatalf("Feed = %v, want two trailing bytes", err)
+	}
+}
+
+type stalledState struct {}
+
+func(state stalledState) Update([]byte) (int, bool) {
+	return 0, false
+}
+
+func(state stalledState) EndPacket() error {
+	return nil
+}
+
+func TestFeedStall(t *testing.T) {
+	var stallError *StallError
+	if err := Feed(stalledState{}, [][]byte{{1}}); !errors.As(err, &stallError) {
+		t.Fatalf("Feed = %v, want stall", err)
+	}
+}